@@ -0,0 +1,386 @@
+package spectrum
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+)
+
+// --- bits （ビット位置） ---
+//
+// ビット位置は最下位ビットを0とするLSB-firstで，Rsh/Lshのシフト方向の規約と一致します．
+
+// Rank は，[0, i)の範囲にある1ビット数を返します．
+// Rank(0)は0，Rank(Len())はOnesCount()と一致します．
+func (s *Spectrum) Rank(i int) (uint, error) {
+	if i < 0 || s.length < i {
+		return 0, fmt.Errorf("%w: index %d is out of [0, %d]", ErrOutOfRange, i, s.length)
+	}
+
+	return onesCount(big.NewInt(0).And(s.bitVector, mask(i))), nil
+}
+
+// OnesCountRange は，[lo, hi)の範囲にある1ビット数を返します．
+// Rank(hi)-Rank(lo)と一致します．範囲が不正な場合はエラーを返します．
+func (s *Spectrum) OnesCountRange(lo, hi int) (uint, error) {
+	if err := s.checkRange(lo, hi); err != nil {
+		return 0, err
+	}
+
+	return onesCount(big.NewInt(0).And(s.bitVector, rangeMask(lo, hi))), nil
+}
+
+// Select は，k番目（1始まり）の1ビットの位置を返します．
+// 1ビット数がk未満の場合はエラーを返します．
+func (s *Spectrum) Select(k uint) (int, error) {
+	if k == 0 {
+		return 0, fmt.Errorf("%w: k must be greater than 0", ErrOutOfRange)
+	}
+
+	r := k
+	for wi, w := range s.bitVector.Bits() {
+		c := uint(bits.OnesCount(uint(w)))
+		if c < r {
+			r -= c
+			continue
+		}
+
+		v := uint(w)
+		for ; 1 < r; r-- {
+			v &= v - 1
+		}
+		return wi*bits.UintSize + bits.TrailingZeros(v), nil
+	}
+
+	return 0, fmt.Errorf("%w: Spectrum has fewer than %d set bits", ErrOutOfRange, k)
+}
+
+// WindowOnesCount は，最下位ビットからwindowビットごとに区切った各窓の1ビット数を返します．
+// Spectrumの長さがwindowで割り切れない場合，最後の窓はwindowビットより短くなります．
+func (s *Spectrum) WindowOnesCount(window int) ([]uint, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("%w: window must be greater than 0, got %d", ErrOutOfRange, window)
+	}
+
+	counts := make([]uint, (s.length+window-1)/window)
+	s.ForEachSetBit(func(i int) bool {
+		counts[i/window]++
+		return true
+	})
+
+	return counts, nil
+}
+
+// FirstSetBit は，最下位の1ビットの位置を返します．1ビットが存在しない場合は-1を返します．
+func (s *Spectrum) FirstSetBit() int {
+	if s.IsZero() {
+		return -1
+	}
+
+	return int(s.bitVector.TrailingZeroBits())
+}
+
+// LastSetBit は，最上位の1ビットの位置を返します．1ビットが存在しない場合は-1を返します．
+func (s *Spectrum) LastSetBit() int {
+	return s.bitVector.BitLen() - 1
+}
+
+// NextSetBit は，from以上の位置にある最初の1ビットの位置を返します．
+// fromが負の場合は0として扱い，Spectrumの長さまでに1ビットが存在しない場合は-1を返します．
+func (s *Spectrum) NextSetBit(from int) int {
+	if from < 0 {
+		from = 0
+	}
+	if s.length <= from {
+		return -1
+	}
+
+	words := s.bitVector.Bits()
+	for wi := from / bits.UintSize; wi < len(words); wi++ {
+		w := uint(words[wi])
+		if wi == from/bits.UintSize {
+			w &^= 1<<uint(from%bits.UintSize) - 1
+		}
+		if w != 0 {
+			return wi*bits.UintSize + bits.TrailingZeros(w)
+		}
+	}
+
+	return -1
+}
+
+// ForEachSetBit は，1ビットの位置ごとに昇順でfnを呼び出します．
+// 位置はLSB-firstで，fnがfalseを返した時点で走査を終了します．
+func (s *Spectrum) ForEachSetBit(fn func(i int) bool) {
+	for wi, w := range s.bitVector.Bits() {
+		for v := uint(w); v != 0; v &= v - 1 {
+			if !fn(wi*bits.UintSize + bits.TrailingZeros(v)) {
+				return
+			}
+		}
+	}
+}
+
+// ForEachClearBit は，[0, Len())の範囲にある0ビットの位置ごとに昇順でfnを呼び出します．
+// 位置はLSB-firstで，fnがfalseを返した時点で走査を終了します．
+func (s *Spectrum) ForEachClearBit(fn func(i int) bool) {
+	words := s.bitVector.Bits()
+	for i := 0; i < s.length; i += bits.UintSize {
+		var w uint
+		if wi := i / bits.UintSize; wi < len(words) {
+			w = uint(words[wi])
+		}
+
+		for v := ^w; v != 0; v &= v - 1 {
+			j := i + bits.TrailingZeros(v)
+			if s.length <= j {
+				return
+			}
+			if !fn(j) {
+				return
+			}
+		}
+	}
+}
+
+// Fold は，初期値initから始めて，[0, Len())の各位置について最下位ビットから昇順に
+// fn(acc, bit, index)を呼び出し，その戻り値を次のaccとして畳み込んだ結果を返します．
+// bitはindexビット目の値（0または1）です．
+// ex. 位置で重み付けした和: s.Fold(0, func(acc, bit, i int) int { return acc + bit*i })
+func (s *Spectrum) Fold(init int, fn func(acc int, bit int, index int) int) int {
+	acc := init
+	for i := 0; i < s.length; i++ {
+		acc = fn(acc, int(s.bitVector.Bit(i)), i)
+	}
+
+	return acc
+}
+
+// Positions は，1ビットの位置を昇順に並べたスライスを返します．
+// 1ビットが存在しない場合は空のスライスを返します．
+func (s *Spectrum) Positions() []int {
+	idx := make([]int, 0, s.OnesCount())
+	s.ForEachSetBit(func(i int) bool {
+		idx = append(idx, i)
+		return true
+	})
+
+	return idx
+}
+
+// SetPositions は，指定した位置のビットのみを1に設定し，それ以外のビットを0にします．
+// 範囲外の位置が含まれる場合は，bitVectorを変更せずにエラーを返します．
+func (s *Spectrum) SetPositions(idx ...int) error {
+	b := big.NewInt(0)
+	for _, i := range idx {
+		if err := s.checkIndex(i); err != nil {
+			return err
+		}
+		b.SetBit(b, i, 1)
+	}
+
+	s.bitVector.Set(b)
+	s.recount()
+	return nil
+}
+
+// NewSpectrumFromPositions は，positionsの位置のビットのみを1に設定した長さlengthのSpectrumを宣言して返します．
+// Positionsの逆操作です．範囲外の位置，または重複した位置が含まれる場合はエラーを返します．
+func NewSpectrumFromPositions(length uint, positions []int) (*Spectrum, error) {
+	s, err := NewSpectrum(length)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, i := range positions {
+		if err := s.checkIndex(i); err != nil {
+			return nil, err
+		}
+		if s.bitVector.Bit(i) == 1 {
+			return nil, fmt.Errorf("%w: position %d is duplicated", ErrInvalidArgument, i)
+		}
+		s.bitVector.SetBit(s.bitVector, i, 1)
+	}
+
+	return s, nil
+}
+
+// ToBoolSlice は，長さLen()の[]boolを返します．
+// インデックスiはiビット目（LSB-first）に対応し，TestBitの規約と一致します．
+func (s *Spectrum) ToBoolSlice() []bool {
+	b := make([]bool, s.length)
+	s.ForEachSetBit(func(i int) bool {
+		b[i] = true
+		return true
+	})
+
+	return b
+}
+
+// FromBoolSlice は，[]boolから長さlen(b)のSpectrumを宣言して返します．
+// インデックスiはiビット目（LSB-first）に対応します．
+func FromBoolSlice(b []bool) (*Spectrum, error) {
+	s, err := NewSpectrum(uint(len(b)))
+	if err != nil {
+		return nil, err
+	}
+
+	for i, v := range b {
+		if v {
+			s.bitVector.SetBit(s.bitVector, i, 1)
+		}
+	}
+
+	return s, nil
+}
+
+// SetRange は，[lo, hi)の範囲のビットをすべて1に設定します．
+func (s *Spectrum) SetRange(lo, hi int) error {
+	if err := s.checkRange(lo, hi); err != nil {
+		return err
+	}
+
+	s.bitVector.Or(s.bitVector, rangeMask(lo, hi))
+	s.recount()
+	return nil
+}
+
+// ClearRange は，[lo, hi)の範囲のビットをすべて0に設定します．
+func (s *Spectrum) ClearRange(lo, hi int) error {
+	if err := s.checkRange(lo, hi); err != nil {
+		return err
+	}
+
+	s.bitVector.AndNot(s.bitVector, rangeMask(lo, hi))
+	s.recount()
+	return nil
+}
+
+// ToggleRange は，[lo, hi)の範囲のビットをすべて反転します．
+// ToggleRange(0, Len())はNotと同じ結果をsに設定します．
+func (s *Spectrum) ToggleRange(lo, hi int) error {
+	if err := s.checkRange(lo, hi); err != nil {
+		return err
+	}
+
+	s.bitVector.Xor(s.bitVector, rangeMask(lo, hi))
+	s.recount()
+	return nil
+}
+
+// ExtractField は，[lo, lo+width)の範囲のビットを取り出したwidthビットのSpectrumを返します．
+func (s *Spectrum) ExtractField(lo, width int) (*Spectrum, error) {
+	if err := s.checkRange(lo, lo+width); err != nil {
+		return nil, err
+	}
+
+	f, err := NewSpectrum(uint(width))
+	if err != nil {
+		return nil, err
+	}
+
+	b := big.NewInt(0).Rsh(s.bitVector, uint(lo))
+	return f.Set(b.And(b, mask(width)))
+}
+
+// InsertField は，[lo, lo+field.Len())の範囲のビットをfieldのbitVectorで置き換えます．
+func (s *Spectrum) InsertField(lo int, field *Spectrum) error {
+	if err := s.checkRange(lo, lo+field.Len()); err != nil {
+		return err
+	}
+
+	b := big.NewInt(0).Lsh(field.bitVector, uint(lo))
+	s.bitVector.AndNot(s.bitVector, rangeMask(lo, lo+field.Len())).Or(s.bitVector, b)
+	s.recount()
+	return nil
+}
+
+// Chunks は，最下位ビットからwidthビットごとに区切った各フィールドの値をuint64型で返します．
+// 結果の要素数はceil(length/width)であり，Spectrumの長さがwidthで割り切れない場合，最後のフィールドはwidthビットより短くなります．
+// widthが[1, 64]の範囲外の場合はエラーを返します．
+func (s *Spectrum) Chunks(width int) ([]uint64, error) {
+	if width <= 0 || 64 < width {
+		return nil, fmt.Errorf("%w: width %d is out of [1, 64]", ErrOutOfRange, width)
+	}
+
+	chunks := make([]uint64, (s.length+width-1)/width)
+	m := mask(width)
+	b := big.NewInt(0)
+	for i := range chunks {
+		b.Rsh(s.bitVector, uint(i*width))
+		chunks[i] = b.And(b, m).Uint64()
+	}
+
+	return chunks, nil
+}
+
+// SwapRange は，[aLo, aLo+width)と[bLo, bLo+width)の範囲のビットを交換します．
+// aLoとbLoが等しい場合，またはwidthが0の場合は何もしません．範囲が重なる場合またはSpectrumの長さを超える場合はエラーを返します．
+func (s *Spectrum) SwapRange(aLo, bLo, width int) error {
+	if err := s.checkRange(aLo, aLo+width); err != nil {
+		return err
+	}
+	if err := s.checkRange(bLo, bLo+width); err != nil {
+		return err
+	}
+	if aLo == bLo || width == 0 {
+		return nil
+	}
+	if aLo < bLo+width && bLo < aLo+width {
+		return fmt.Errorf("%w: ranges [%d, %d) and [%d, %d) overlap", ErrInvalidArgument, aLo, aLo+width, bLo, bLo+width)
+	}
+
+	a, err := s.ExtractField(aLo, width)
+	if err != nil {
+		return err
+	}
+	b, err := s.ExtractField(bLo, width)
+	if err != nil {
+		return err
+	}
+	if err := s.InsertField(aLo, b); err != nil {
+		return err
+	}
+
+	return s.InsertField(bLo, a)
+}
+
+// checkRange は，[lo, hi)がSpectrumの長さの範囲内にあるかを検査します．
+func (s *Spectrum) checkRange(lo, hi int) error {
+	if lo < 0 || hi < lo || s.length < hi {
+		return fmt.Errorf("%w: range [%d, %d) is out of [0, %d)", ErrOutOfRange, lo, hi, s.length)
+	}
+
+	return nil
+}
+
+// rangeMask は，[lo, hi)の範囲のビットがすべて1のbig.Intを返します．
+func rangeMask(lo, hi int) *big.Int {
+	m := mask(hi - lo)
+
+	return m.Lsh(m, uint(lo))
+}
+
+// NextCombination は，1ビット数を保ったまま，bitVectorの値が次に大きい組み合わせを持つ新しいSpectrumを返します（Gosper's hack）．
+// Spectrumの長さの範囲内に次の組み合わせが存在しない場合，またはbitVectorが0の場合はfalseを返します．
+// ex. 0b0011 -> 0b0101 -> 0b0110 -> 0b1001 -> 0b1010 -> 0b1100
+func (s *Spectrum) NextCombination() (*Spectrum, bool) {
+	if s.IsZero() {
+		return nil, false
+	}
+
+	x := s.bitVector
+	tz := x.TrailingZeroBits()
+	r := big.NewInt(0).SetBit(big.NewInt(0), int(tz), 1)
+	r.Add(r, x)
+
+	b := big.NewInt(0).Xor(r, x)
+	b.Rsh(b, 2+tz).Or(b, r)
+	if s.length < b.BitLen() {
+		return nil, false
+	}
+
+	ns := s.clone()
+	ns.Set(b)
+	return ns, true
+}
@@ -0,0 +1,71 @@
+package spectrum
+
+import "hash/fnv"
+
+// --- BloomFilter ---
+
+// BloomFilter は，Spectrumをビット配列として用いるBloomフィルタです．
+// 要素ごとに2つの基本ハッシュ値からダブルハッシングでhashes個のビット位置を求めます．
+type BloomFilter struct {
+	s      *Spectrum
+	hashes int
+}
+
+// NewBloomFilter は，bitsビットのビット配列とhashes個のハッシュ関数を持つBloomFilterを宣言して返します．
+// bitsは1以上MaxLength以下，hashesは1以上に補正されます．
+func NewBloomFilter(bits uint, hashes int) *BloomFilter {
+	if bits == 0 {
+		bits = 1
+	}
+	if MaxLength < bits {
+		bits = MaxLength
+	}
+	if hashes < 1 {
+		hashes = 1
+	}
+
+	s, _ := NewSpectrum(bits)
+	return &BloomFilter{s: s, hashes: hashes}
+}
+
+// Add は，dataをBloomFilterに追加します．
+func (bf *BloomFilter) Add(data []byte) {
+	bf.each(data, func(i int) bool {
+		bf.s.SetBit(i)
+		return true
+	})
+}
+
+// Test は，dataがBloomFilterに含まれる可能性があるかを返します．
+// falseの場合は確実に含まれず，trueの場合は偽陽性の可能性があります．
+func (bf *BloomFilter) Test(data []byte) bool {
+	ok := true
+	bf.each(data, func(i int) bool {
+		ok = bf.s.bitVector.Bit(i) == 1
+		return ok
+	})
+
+	return ok
+}
+
+// Spectrum は，BloomFilterのビット配列の複製を返します．
+func (bf *BloomFilter) Spectrum() *Spectrum {
+	return bf.s.Copy()
+}
+
+// each は，dataに対応するhashes個のビット位置ごとにfnを呼び出します．
+// fnがfalseを返した時点で終了します．
+func (bf *BloomFilter) each(data []byte, fn func(i int) bool) {
+	h1 := fnv.New64a()
+	h1.Write(data)
+	h2 := fnv.New64()
+	h2.Write(data)
+
+	a, b := h1.Sum64(), h2.Sum64()|1
+	m := uint64(bf.s.Len())
+	for i := 0; i < bf.hashes; i++ {
+		if !fn(int((a + uint64(i)*b) % m)) {
+			return
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package spectrum
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+)
+
+// --- encoding （エンコーディング） ---
+
+// binaryLengthSize は，MarshalBinaryが出力する長さプレフィックスのバイト数です．
+const binaryLengthSize = 8
+
+// MarshalBinary は，encoding.BinaryMarshalerを実装します．
+// 出力は8バイトのビッグエンディアンで表現した長さと，Bytesと同じceil(length/8)バイトの固定長のバイト列を連結したものです．
+func (s *Spectrum) MarshalBinary() ([]byte, error) {
+	b := make([]byte, binaryLengthSize, binaryLengthSize+s.ByteLen())
+	binary.BigEndian.PutUint64(b, uint64(s.length))
+
+	return append(b, s.Bytes()...), nil
+}
+
+// UnmarshalBinary は，encoding.BinaryUnmarshalerを実装します．
+// MarshalBinaryが出力したバイト列からSpectrumの長さとbitVectorを復元します．
+func (s *Spectrum) UnmarshalBinary(data []byte) error {
+	if len(data) < binaryLengthSize {
+		return fmt.Errorf("%w: binary data of %d bytes is too short", ErrParse, len(data))
+	}
+
+	l, err := decodeLength(data[:binaryLengthSize])
+	if err != nil {
+		return err
+	}
+	if len(data) != binaryLengthSize+(int(l)+7)/8 {
+		return fmt.Errorf("%w: binary data of %d bytes does not match length %d", ErrParse, len(data), l)
+	}
+
+	return s.decode(l, big.NewInt(0).SetBytes(data[binaryLengthSize:]))
+}
+
+// WriteTo は，io.WriterToを実装します．
+// MarshalBinaryと同じ形式でwに書き込みます．
+func (s *Spectrum) WriteTo(w io.Writer) (int64, error) {
+	b, err := s.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+// ReadFrom は，io.ReaderFromを実装します．
+// WriteToまたはMarshalBinaryが出力した形式のSpectrumを1つだけrから読み込みます．
+// 1バイトも読み込まずにrが終端に達した場合は，io.EOFをそのまま返します．
+// 読み込みが途中で終了した場合はエラーを返し，sは変更されません．
+func (s *Spectrum) ReadFrom(r io.Reader) (int64, error) {
+	h := make([]byte, binaryLengthSize)
+	n, err := io.ReadFull(r, h)
+	if err == io.EOF {
+		return 0, err
+	}
+	if err != nil {
+		return int64(n), fmt.Errorf("%w: failed to read length: %v", ErrParse, err)
+	}
+
+	l, err := decodeLength(h)
+	if err != nil {
+		return int64(n), err
+	}
+
+	b := make([]byte, (l+7)/8)
+	m, err := io.ReadFull(r, b)
+	if err != nil {
+		return int64(n + m), fmt.Errorf("%w: failed to read bitVector: %v", ErrParse, err)
+	}
+
+	return int64(n + m), s.decode(l, big.NewInt(0).SetBytes(b))
+}
+
+// decodeLength は，8バイトのビッグエンディアンで表現した長さを復号します．
+func decodeLength(b []byte) (uint, error) {
+	l := binary.BigEndian.Uint64(b)
+	if uint64(MaxLength) < l {
+		return 0, fmt.Errorf("%w: decoded length %d exceeds MaxLength(%d)", ErrInvalidLength, l, MaxLength)
+	}
+
+	return uint(l), nil
+}
+
+// GobEncode は，gob.GobEncoderを実装します．
+// 出力はMarshalBinaryと同じ形式（8バイトの長さプレフィックスとbitVectorのバイト列）です．
+func (s *Spectrum) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+// GobDecode は，gob.GobDecoderを実装します．
+// GobEncodeが出力したバイト列からSpectrumの長さとbitVectorを復元します．
+func (s *Spectrum) GobDecode(data []byte) error {
+	return s.UnmarshalBinary(data)
+}
+
+// Base64 は，MarshalBinaryと同じ形式のバイト列を標準のbase64でエンコードした文字列を返します．
+// 先頭8バイトの長さプレフィックスを含むため，8の倍数でない長さのSpectrumもSetBase64で正確に復元できます．
+func (s *Spectrum) Base64() string {
+	b, _ := s.MarshalBinary()
+
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// SetBase64 は，Base64が出力した文字列からSpectrumの長さとbitVectorを復元します．
+// sの長さは文字列に含まれる長さプレフィックスの値に変更されます．
+func (s *Spectrum) SetBase64(str string) (*Spectrum, error) {
+	b, err := base64.StdEncoding.DecodeString(str)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q is not a base64 string: %v", ErrParse, str, err)
+	}
+	if err := s.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// jsonSpectrum は，SpectrumのJSON表現です．
+type jsonSpectrum struct {
+	Length *uint   `json:"length"`
+	Hex    *string `json:"hex"`
+}
+
+// MarshalJSON は，json.Marshalerを実装します．
+// ex. {"length":64,"hex":"0x00000000ffffffff"}
+func (s *Spectrum) MarshalJSON() ([]byte, error) {
+	l, h := uint(s.length), s.Hex()
+
+	return json.Marshal(jsonSpectrum{Length: &l, Hex: &h})
+}
+
+// UnmarshalJSON は，json.Unmarshalerを実装します．
+// "length"と"hex"の両方が必要であり，未知のフィールドが含まれる場合はエラーを返します．
+// encoding/jsonの慣例に従い，JSONのnullに対しては何もせずnilを返します．
+func (s *Spectrum) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+
+	var js jsonSpectrum
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&js); err != nil {
+		return err
+	}
+	if js.Length == nil || js.Hex == nil {
+		return fmt.Errorf("%w: JSON object requires \"length\" and \"hex\"", ErrParse)
+	}
+
+	v, ok := big.NewInt(0).SetString(strings.TrimPrefix(*js.Hex, "0x"), 16)
+	if !ok {
+		return fmt.Errorf("%w: %q is not a hex string", ErrParse, *js.Hex)
+	}
+
+	return s.decode(*js.Length, v)
+}
+
+// Value は，driver.Valuerを実装します．
+// データベースにはMarshalBinaryと同じ形式（8バイトの長さプレフィックスとbitVectorのバイト列）で保存されます．
+func (s Spectrum) Value() (driver.Value, error) {
+	return s.MarshalBinary()
+}
+
+// Scan は，sql.Scannerを実装します．
+// srcには，Valueが出力した形式の[]byteまたはstringを指定します．
+func (s *Spectrum) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return s.UnmarshalBinary(v)
+	case string:
+		return s.UnmarshalBinary([]byte(v))
+	default:
+		return fmt.Errorf("%w: cannot scan %T into Spectrum", ErrInvalidArgument, src)
+	}
+}
+
+// decode は，長さlengthと値xを検証してsに設定します．
+// sがゼロ値の場合でも利用できるよう，新しいSpectrumを宣言してから置き換えます．
+func (s *Spectrum) decode(length uint, x *big.Int) error {
+	ns, err := NewSpectrum(length)
+	if err != nil {
+		return err
+	}
+	if _, err := ns.Set(x); err != nil {
+		return err
+	}
+
+	if s.rnd != nil {
+		ns.rnd, ns.src = s.rnd, s.src
+	}
+	*s = *ns
+	return nil
+}
+
+// RunLengthEncode は，bitVectorを最下位ビットから走査した連長（ランレングス）を返します．
+// 連長は0ビットの連続から始まり，0と1の連続が交互に並びます．
+// 最下位ビットが1の場合，先頭の連長は0となります．連長の合計はLen()と一致します．
+// ex. 0b1110011 -> [0, 2, 2, 3]
+func (s *Spectrum) RunLengthEncode() []int {
+	runs := []int{}
+	var bit uint
+	var n int
+	for i := 0; i < s.length; i++ {
+		if b := s.bitVector.Bit(i); b != bit {
+			runs = append(runs, n)
+			bit, n = b, 0
+		}
+		n++
+	}
+
+	return append(runs, n)
+}
+
+// RunLengthDecode は，RunLengthEncodeが出力した連長からSpectrumを復元します．
+// Spectrumの長さは連長の合計となります．
+func RunLengthDecode(runs []int) (*Spectrum, error) {
+	var l int
+	for _, n := range runs {
+		if n < 0 {
+			return nil, fmt.Errorf("%w: run length must not be negative, got %d", ErrInvalidArgument, n)
+		}
+		l += n
+	}
+
+	s, err := NewSpectrum(uint(l))
+	if err != nil {
+		return nil, err
+	}
+
+	var i int
+	for k, n := range runs {
+		if k%2 == 1 {
+			s.bitVector.Or(s.bitVector, rangeMask(i, i+n))
+		}
+		i += n
+	}
+
+	return s, nil
+}
@@ -0,0 +1,35 @@
+package spectrum
+
+import (
+	"errors"
+	"fmt"
+)
+
+// --- errors （エラー） ---
+//
+// spectrum パッケージが返すエラーは，以下のいずれかのエラーをラップしています．
+// errors.Is を用いて，エラーの種類を判別できます．
+
+var (
+	// ErrInvalidLength は，Spectrumの長さが不正であることを表します．
+	ErrInvalidLength = errors.New("spectrum: invalid length")
+	// ErrTooLong は，値のビット数がSpectrumの長さを超えていることを表します．
+	ErrTooLong = errors.New("spectrum: value is too long for length of Spectrum")
+	// ErrNegative は，負の値が指定されたことを表します．
+	ErrNegative = errors.New("spectrum: value must not be negative")
+	// ErrParse は，文字列やバイト列の変換に失敗したことを表します．
+	ErrParse = errors.New("spectrum: failed to parse")
+	// ErrLengthMismatch は，Spectrumの長さが一致しないことを表します．
+	ErrLengthMismatch = errors.New("spectrum: length of Spectrums does not match")
+	// ErrOutOfRange は，位置や範囲などの引数が有効な範囲外であることを表します．
+	ErrOutOfRange = errors.New("spectrum: out of range")
+	// ErrEmpty は，Spectrumが1つも指定されていないことを表します．
+	ErrEmpty = errors.New("spectrum: no Spectrum is given")
+	// ErrInvalidArgument は，上記以外の不正な引数が指定されたことを表します．
+	ErrInvalidArgument = errors.New("spectrum: invalid argument")
+)
+
+// lengthMismatch は，長さaとbが一致しないことを表すErrLengthMismatchをラップしたエラーを返します．
+func lengthMismatch(a, b int) error {
+	return fmt.Errorf("%w: %d and %d", ErrLengthMismatch, a, b)
+}
@@ -0,0 +1,89 @@
+package spectrum
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// --- GF(2) polynomial arithmetic （GF(2)上の多項式演算） ---
+//
+// bitVectorのiビット目をx^iの係数とみなし，Spectrumを GF(2) 上の多項式として扱います．
+
+// CLMul は，2つのSpectrumの繰り上がりなし乗算（carry-less multiplication）の結果を返します．
+// Spectrumの長さはa.Len()+b.Len()となります．
+func CLMul(a, b *Spectrum) (*Spectrum, error) {
+	p := big.NewInt(0)
+	t := big.NewInt(0)
+	b.ForEachSetBit(func(i int) bool {
+		p.Xor(p, t.Lsh(a.bitVector, uint(i)))
+		return true
+	})
+
+	s, err := NewSpectrum(uint(a.Len() + b.Len()))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Set(p)
+}
+
+// GF2Mod は，sをmodulusで割った余りの多項式を返します．
+// Spectrumの長さはmodulusの次数（ただし最小1）となります．modulusが0の場合はエラーを返します．
+func (s *Spectrum) GF2Mod(modulus *Spectrum) (*Spectrum, error) {
+	if modulus.IsZero() {
+		return nil, fmt.Errorf("%w: modulus must not be zero", ErrInvalidArgument)
+	}
+
+	m := modulus.bitVector
+	r := s.BigInt()
+	t := big.NewInt(0)
+	for m.BitLen() <= r.BitLen() {
+		r.Xor(r, t.Lsh(m, uint(r.BitLen()-m.BitLen())))
+	}
+
+	l := m.BitLen() - 1
+	if l < 1 {
+		l = 1
+	}
+
+	ns, err := NewSpectrum(uint(l))
+	if err != nil {
+		return nil, err
+	}
+
+	return ns.Set(r)
+}
+
+// CRC は，生成多項式polyによるdataのCRC値を返します．
+// CRC値は，dataにx^(poly.Len()-1)を乗じた多項式をpolyで割った余りです．
+// 初期値0，反転なし，最終XORなしのCRCに相当し，dataの最上位ビットから順に処理したものと一致します．
+// Spectrumの長さはpoly.Len()-1となります．polyの最上位ビット（poly.Len()-1ビット目）が0の場合，
+// またはpolyの長さが2未満の場合はエラーを返します．
+// ex. CRC-8（x^8+x^2+x+1）の場合，polyは長さ9のSpectrumで0x107を指定します．
+func CRC(data, poly *Spectrum) (*Spectrum, error) {
+	if poly.Len() < 2 || poly.bitVector.Bit(poly.Len()-1) == 0 {
+		return nil, fmt.Errorf("%w: top bit of generator polynomial %s must be set", ErrInvalidArgument, poly.Bit())
+	}
+
+	d, err := NewSpectrum(uint(data.Len() + poly.Len() - 1))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := d.Set(big.NewInt(0).Lsh(data.bitVector, uint(poly.Len()-1))); err != nil {
+		return nil, err
+	}
+
+	return d.GF2Mod(poly)
+}
+
+// --- GF(2) linear algebra （GF(2)上の線形代数） ---
+
+// Dot は，2つのSpectrumをGF(2)上のベクトルとみなした内積（a AND b の1ビット数の偶奇）を返します．
+// パリティ検査行列の各行と符号語の検査などに使用できます．長さが異なる場合はエラーを返します．
+func Dot(a, b *Spectrum) (int, error) {
+	if a.Len() != b.Len() {
+		return 0, lengthMismatch(a.Len(), b.Len())
+	}
+
+	return int(onesCount(And(a, b)) & 1), nil
+}
@@ -1,33 +1,245 @@
 package spectrum
 
-import "math/big"
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"strings"
+)
 
 // --- bitwise operation (ビット演算) ---
+//
+// ビット演算は，長さの異なるSpectrumどうしでもエラーを返しません．
+// 短い方のSpectrumは上位ビットを0で拡張したものとして扱われるため，
+// 結果は常に2つのSpectrumの長さのうち大きい方に収まります．
+// 長さの一致を要求する場合は，DifferenceやAndWithなどを用いてください．
 
 // And は，2つのSpectrumのbitVectorをAND比較します．
+// 結果のビット長は2つのSpectrumの長さのうち小さい方を超えません．
 func And(source *Spectrum, target *Spectrum) *big.Int {
 	return big.NewInt(0).And(source.bitVector, target.bitVector)
 }
 
 // OR は，2つのSpectrumのbitVectorをOR比較します．
+// 結果のビット長は2つのSpectrumの長さのうち大きい方を超えません．
 func Or(source *Spectrum, target *Spectrum) *big.Int {
 	return big.NewInt(0).Or(source.bitVector, target.bitVector)
 }
 
 // AndNot は，2つのSpectrumのbitVectorをANDNOT比較します．
+// 結果のビット長はsourceの長さを超えません．
 func AndNot(source *Spectrum, target *Spectrum) *big.Int {
 	return big.NewInt(0).AndNot(source.bitVector, target.bitVector)
 }
 
 // Xor は，2つのSpectrumのbitVectorをXOR比較します．
+// 結果のビット長は2つのSpectrumの長さのうち大きい方を超えません．
 func Xor(source *Spectrum, target *Spectrum) *big.Int {
 	return big.NewInt(0).Xor(source.bitVector, target.bitVector)
 }
 
+// AndS は，2つのSpectrumのbitVectorをAND比較したSpectrumを返します．
+// Spectrumの長さは2つのSpectrumの長さのうち大きい方となります．
+func AndS(a, b *Spectrum) (*Spectrum, error) {
+	return bitwise(a, b, And(a, b))
+}
+
+// OrS は，2つのSpectrumのbitVectorをOR比較したSpectrumを返します．
+// Spectrumの長さは2つのSpectrumの長さのうち大きい方となります．
+func OrS(a, b *Spectrum) (*Spectrum, error) {
+	return bitwise(a, b, Or(a, b))
+}
+
+// AndNotS は，2つのSpectrumのbitVectorをANDNOT比較したSpectrumを返します．
+// Spectrumの長さは2つのSpectrumの長さのうち大きい方となります．
+func AndNotS(a, b *Spectrum) (*Spectrum, error) {
+	return bitwise(a, b, AndNot(a, b))
+}
+
+// XorS は，2つのSpectrumのbitVectorをXOR比較したSpectrumを返します．
+// Spectrumの長さは2つのSpectrumの長さのうち大きい方となります．
+func XorS(a, b *Spectrum) (*Spectrum, error) {
+	return bitwise(a, b, Xor(a, b))
+}
+
+// Difference は，aで1かつbで0のビット（a AND NOT b）を持つSpectrumを返します．
+// 2つのSpectrumの長さが異なる場合はエラーを返します．
+func Difference(a, b *Spectrum) (*Spectrum, error) {
+	if a.Len() != b.Len() {
+		return nil, lengthMismatch(a.Len(), b.Len())
+	}
+
+	return AndNotS(a, b)
+}
+
+// SymmetricDifference は，aとbのいずれか一方のみで1のビット（a XOR b）を持つSpectrumを返します．
+// 2つのSpectrumの長さが異なる場合はエラーを返します．
+func SymmetricDifference(a, b *Spectrum) (*Spectrum, error) {
+	if a.Len() != b.Len() {
+		return nil, lengthMismatch(a.Len(), b.Len())
+	}
+
+	return XorS(a, b)
+}
+
+// AndWith は，sのbitVectorをotherとAND比較した結果で置き換えます．
+// 新しいbig.Intを確保しないため，繰り返し演算する場合はAndより高速です．
+func (s *Spectrum) AndWith(other *Spectrum) error {
+	if s.Len() != other.Len() {
+		return lengthMismatch(s.Len(), other.Len())
+	}
+
+	s.bitVector.And(s.bitVector, other.bitVector)
+	s.recount()
+	return nil
+}
+
+// OrWith は，sのbitVectorをotherとOR比較した結果で置き換えます．
+func (s *Spectrum) OrWith(other *Spectrum) error {
+	if s.Len() != other.Len() {
+		return lengthMismatch(s.Len(), other.Len())
+	}
+
+	s.bitVector.Or(s.bitVector, other.bitVector)
+	s.recount()
+	return nil
+}
+
+// XorWith は，sのbitVectorをotherとXOR比較した結果で置き換えます．
+func (s *Spectrum) XorWith(other *Spectrum) error {
+	if s.Len() != other.Len() {
+		return lengthMismatch(s.Len(), other.Len())
+	}
+
+	s.bitVector.Xor(s.bitVector, other.bitVector)
+	s.recount()
+	return nil
+}
+
+// OrAll は，すべてのSpectrumのbitVectorをOR比較したSpectrumを返します．
+// Spectrumが指定されていない場合，または長さが一致しない場合はエラーを返します．
+func OrAll(specs ...*Spectrum) (*Spectrum, error) {
+	if err := sameLength(specs); err != nil {
+		return nil, err
+	}
+
+	acc := specs[0].clone()
+	for _, s := range specs[1:] {
+		acc.bitVector.Or(acc.bitVector, s.bitVector)
+	}
+
+	return acc, nil
+}
+
+// AndAll は，すべてのSpectrumのbitVectorをAND比較したSpectrumを返します．
+// Spectrumが指定されていない場合，または長さが一致しない場合はエラーを返します．
+func AndAll(specs ...*Spectrum) (*Spectrum, error) {
+	if err := sameLength(specs); err != nil {
+		return nil, err
+	}
+
+	acc := specs[0].clone()
+	for _, s := range specs[1:] {
+		acc.bitVector.And(acc.bitVector, s.bitVector)
+	}
+
+	return acc, nil
+}
+
+// Majority は，各ビットについて過半数のSpectrumで1であれば1となるSpectrumを返します．
+// Spectrumの数が偶数で同数となったビットは0になります．
+// Spectrumが指定されていない場合，または長さが一致しない場合はエラーを返します．
+func Majority(specs ...*Spectrum) (*Spectrum, error) {
+	if err := sameLength(specs); err != nil {
+		return nil, err
+	}
+
+	m := specs[0].clone()
+	m.bitVector.SetInt64(0)
+	for i := 0; i < m.Len(); i++ {
+		var votes int
+		for _, s := range specs {
+			votes += int(s.bitVector.Bit(i))
+		}
+		if len(specs) < 2*votes {
+			m.bitVector.SetBit(m.bitVector, i, 1)
+		}
+	}
+
+	return m, nil
+}
+
+// Max は，bitVectorの値が最大のSpectrumの複製を返します．最大値が複数ある場合は最初のものを返します．
+// Spectrumが指定されていない場合，または長さが一致しない場合はエラーを返します．
+func Max(specs ...*Spectrum) (*Spectrum, error) {
+	if err := sameLength(specs); err != nil {
+		return nil, err
+	}
+
+	m := specs[0]
+	for _, s := range specs[1:] {
+		if 0 < s.Cmp(m) {
+			m = s
+		}
+	}
+
+	return m.clone(), nil
+}
+
+// Min は，bitVectorの値が最小のSpectrumの複製を返します．最小値が複数ある場合は最初のものを返します．
+// Spectrumが指定されていない場合，または長さが一致しない場合はエラーを返します．
+func Min(specs ...*Spectrum) (*Spectrum, error) {
+	if err := sameLength(specs); err != nil {
+		return nil, err
+	}
+
+	m := specs[0]
+	for _, s := range specs[1:] {
+		if s.Cmp(m) < 0 {
+			m = s
+		}
+	}
+
+	return m.clone(), nil
+}
+
+// sameLength は，1つ以上のSpectrumが指定され，すべての長さが一致するかを検査します．
+func sameLength(specs []*Spectrum) error {
+	if len(specs) == 0 {
+		return ErrEmpty
+	}
+
+	for _, s := range specs[1:] {
+		if s.Len() != specs[0].Len() {
+			return lengthMismatch(specs[0].Len(), s.Len())
+		}
+	}
+
+	return nil
+}
+
+// bitwise は，ビット演算の結果xを2つのSpectrumの長さのうち大きい方の長さを持つSpectrumに設定します．
+func bitwise(a, b *Spectrum, x *big.Int) (*Spectrum, error) {
+	l := a.Len()
+	if l < b.Len() {
+		l = b.Len()
+	}
+
+	s, err := NewSpectrum(uint(l))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Set(x.And(x, mask(l)))
+}
+
 // --- shift operation (シフト演算) ---
 
 // Rsh は，bitVectorを循環論理右シフトした新しいSpectrumを返します．
+// シフト数nはSpectrumの長さを法として扱われます．
 func Rsh(s *Spectrum, n uint) *Spectrum {
+	n %= uint(s.Len())
 	b := s.BigInt()
 	for i := 0; i < int(n); i++ {
 		if big.NewInt(0).And(b, big.NewInt(1)).Cmp(big.NewInt(1)) == 0 {
@@ -36,13 +248,15 @@ func Rsh(s *Spectrum, n uint) *Spectrum {
 		b.Rsh(b, 1)
 	}
 
-	sh := s.Copy()
+	sh := s.clone()
 	sh.Set(b)
 	return sh
 }
 
 // Lsh は，bitVectorを循環論理左シフトした新しいSpectrumを返します．
+// シフト数nはSpectrumの長さを法として扱われます．
 func Lsh(s *Spectrum, n uint) *Spectrum {
+	n %= uint(s.Len())
 	b := s.BigInt()
 	for i := 0; i < int(n); i++ {
 		b.Lsh(b, 1)
@@ -52,7 +266,46 @@ func Lsh(s *Spectrum, n uint) *Spectrum {
 		}
 	}
 
-	sh := s.Copy()
+	sh := s.clone()
+	sh.Set(b)
+	return sh
+}
+
+// Rotate は，bitVectorを循環シフトした新しいSpectrumを返します．
+// nが正の場合は左（Lsh），負の場合は右（Rsh）に|n|ビット循環シフトします．
+func Rotate(s *Spectrum, n int) *Spectrum {
+	if n < 0 {
+		return Rsh(s, uint(-n%s.Len()))
+	}
+
+	return Lsh(s, uint(n%s.Len()))
+}
+
+// ShiftLeft は，bitVectorを非循環の論理左シフトした新しいSpectrumを返します．
+// Spectrumの長さを超えたビットは破棄され，下位ビットは0で埋められます．
+// nがSpectrumの長さ以上の場合は，シフトせずに全ビットが0のSpectrumを返します．
+func ShiftLeft(s *Spectrum, n uint) *Spectrum {
+	if uint(s.Len()) <= n {
+		sh := s.clone()
+		sh.Clear()
+		return sh
+	}
+
+	b := s.BigInt()
+	b.Lsh(b, n).And(b, mask(s.Len()))
+
+	sh := s.clone()
+	sh.Set(b)
+	return sh
+}
+
+// ShiftRight は，bitVectorを非循環の論理右シフトした新しいSpectrumを返します．
+// 最下位ビットより下にシフトしたビットは破棄され，上位ビットは0で埋められます．
+func ShiftRight(s *Spectrum, n uint) *Spectrum {
+	b := s.BigInt()
+	b.Rsh(b, n)
+
+	sh := s.clone()
 	sh.Set(b)
 	return sh
 }
@@ -75,3 +328,302 @@ func Merge(x, y *Spectrum) (*Spectrum, error) {
 	s.Set(b)
 	return s, nil
 }
+
+// Concat は，複数のSpectrumを左から順に1つのSpectrumに結合します．
+// Mergeと同様に，先に指定したSpectrumほど上位ビットに配置されます．
+// ex. 10 + 01 + 11 -> 100111
+func Concat(parts ...*Spectrum) (*Spectrum, error) {
+	if len(parts) == 0 {
+		return nil, ErrEmpty
+	}
+
+	var l uint
+	b := big.NewInt(0)
+	for _, p := range parts {
+		l += uint(p.Len())
+		b.Lsh(b, uint(p.Len())).Or(b, p.bitVector)
+	}
+
+	s, err := NewSpectrum(l)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Set(b)
+}
+
+// Split は，1つのSpectrumを下位atビットのlowと残りの上位ビットのhighに分割します．
+// Mergeの逆操作であり，Merge(high, low)は元のSpectrumと一致します．
+// ex. 10101001 (at = 4) -> 1010, 1001
+func Split(s *Spectrum, at int) (high, low *Spectrum, err error) {
+	if at <= 0 || s.Len() <= at {
+		return nil, nil, fmt.Errorf("%w: split position %d is out of (0, %d)", ErrOutOfRange, at, s.Len())
+	}
+
+	if low, err = NewSpectrum(uint(at)); err != nil {
+		return nil, nil, err
+	}
+	if high, err = NewSpectrum(uint(s.Len() - at)); err != nil {
+		return nil, nil, err
+	}
+
+	b := s.BigInt()
+	low.Set(big.NewInt(0).And(b, mask(at)))
+	high.Set(b.Rsh(b, uint(at)))
+	return high, low, nil
+}
+
+// Interleave は，xとyのビットを交互に並べた長さ2*Len()のSpectrumを返します（Morton符号）．
+// xのiビット目は2iビット目（偶数位置），yのiビット目は2i+1ビット目（奇数位置）に配置されます．
+// 2つのSpectrumの長さが異なる場合はエラーを返します．
+func Interleave(x, y *Spectrum) (*Spectrum, error) {
+	if x.Len() != y.Len() {
+		return nil, lengthMismatch(x.Len(), y.Len())
+	}
+
+	s, err := NewSpectrum(uint(2 * x.Len()))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < x.Len(); i++ {
+		s.bitVector.SetBit(s.bitVector, 2*i, x.bitVector.Bit(i))
+		s.bitVector.SetBit(s.bitVector, 2*i+1, y.bitVector.Bit(i))
+	}
+
+	return s, nil
+}
+
+// Deinterleave は，Interleaveの逆操作として，偶数位置のビットをx，奇数位置のビットをyに分離します．
+// Spectrumの長さが奇数の場合はエラーを返します．
+func Deinterleave(s *Spectrum) (x, y *Spectrum, err error) {
+	if s.Len()%2 != 0 {
+		return nil, nil, fmt.Errorf("%w: length must be even, got %d", ErrInvalidLength, s.Len())
+	}
+
+	if x, err = NewSpectrum(uint(s.Len() / 2)); err != nil {
+		return nil, nil, err
+	}
+	if y, err = NewSpectrum(uint(s.Len() / 2)); err != nil {
+		return nil, nil, err
+	}
+
+	for i := 0; i < x.Len(); i++ {
+		x.bitVector.SetBit(x.bitVector, i, s.bitVector.Bit(2*i))
+		y.bitVector.SetBit(y.bitVector, i, s.bitVector.Bit(2*i+1))
+	}
+
+	return x, y, nil
+}
+
+// Stride は，offset, offset+step, offset+2*step, ... の位置のビットを下位から詰めた新しいSpectrumを返します．
+// Spectrumの長さは[offset, Len())の範囲に含まれる位置の数となります．
+// stepが1未満の場合，またはoffsetが[0, Len())の範囲外の場合はエラーを返します．
+// ex. Stride(0, 2)とStride(1, 2)はDeinterleaveのxとyに一致します．
+func (s *Spectrum) Stride(offset, step int) (*Spectrum, error) {
+	if step < 1 {
+		return nil, fmt.Errorf("%w: step must be greater than 0, got %d", ErrOutOfRange, step)
+	}
+	if err := s.checkIndex(offset); err != nil {
+		return nil, err
+	}
+
+	ns, err := NewSpectrum(uint((s.length - offset + step - 1) / step))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < ns.length; i++ {
+		ns.bitVector.SetBit(ns.bitVector, i, s.bitVector.Bit(offset+i*step))
+	}
+
+	return ns, nil
+}
+
+// Crossover は，2つのSpectrumを一点交叉した子を返します．
+// child1はaの下位pointビットとbの上位ビット，child2はbの下位pointビットとaの上位ビットを持ちます．
+// 2つのSpectrumの長さが異なる場合，または0 < point < Len()でない場合はエラーを返します．
+func Crossover(a, b *Spectrum, point int) (child1, child2 *Spectrum, err error) {
+	if a.Len() != b.Len() {
+		return nil, nil, lengthMismatch(a.Len(), b.Len())
+	}
+	if point <= 0 || a.Len() <= point {
+		return nil, nil, fmt.Errorf("%w: crossover point %d is out of (0, %d)", ErrOutOfRange, point, a.Len())
+	}
+
+	low := mask(point)
+	cross := func(x, y *Spectrum) *Spectrum {
+		b := big.NewInt(0).AndNot(y.bitVector, low)
+		b.Or(b, big.NewInt(0).And(x.bitVector, low))
+
+		c := x.clone()
+		c.Set(b)
+		return c
+	}
+
+	return cross(a, b), cross(b, a), nil
+}
+
+// Transpose は，Spectrumのスライスを行列とみなして転置したスライスを返します．
+// 出力のj番目のSpectrumのiビット目は，rows[i]のjビット目となります．
+// 出力はrows[0].Len()個の長さlen(rows)のSpectrumです．
+// Spectrumが指定されていない場合，または長さが一致しない場合はエラーを返します．
+func Transpose(rows []*Spectrum) ([]*Spectrum, error) {
+	if err := sameLength(rows); err != nil {
+		return nil, err
+	}
+
+	cols := make([]*Spectrum, rows[0].Len())
+	for j := range cols {
+		c, err := NewSpectrum(uint(len(rows)))
+		if err != nil {
+			return nil, err
+		}
+		cols[j] = c
+	}
+
+	for i, r := range rows {
+		r.ForEachSetBit(func(j int) bool {
+			cols[j].bitVector.SetBit(cols[j].bitVector, i, 1)
+			return true
+		})
+	}
+
+	return cols, nil
+}
+
+// --- spectrum comparison （スペクトル比較） ---
+
+// HammingDistance は，2つのSpectrumで異なるビットの数（hamming-distance）を返します．
+// 2つのSpectrumの長さが異なる場合はエラーを返します．
+func HammingDistance(a, b *Spectrum) (uint, error) {
+	if a.Len() != b.Len() {
+		return 0, lengthMismatch(a.Len(), b.Len())
+	}
+
+	return onesCount(Xor(a, b)), nil
+}
+
+// Diff は，2つのSpectrumで異なるビット（a XOR b の1ビット）の位置を昇順に並べたスライスを返します．
+// 異なるビットが存在しない場合は空のスライスを返し，長さが異なる場合はエラーを返します．
+func Diff(a, b *Spectrum) ([]int, error) {
+	x, err := SymmetricDifference(a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	return x.Positions(), nil
+}
+
+// DiffString は，2つのSpectrumで一致するビットを'.'，異なるビットを'^'で表したLen()文字の文字列を返します．
+// 文字はBitと同じく最上位ビットから順に並ぶため，プレフィックス"0b"を除いたBitの出力の真下に揃えて表示できます．
+// 長さが異なる場合はエラーを返します．
+// ex. 0b1010 と 0b1001 -> "..^^"
+func DiffString(a, b *Spectrum) (string, error) {
+	pos, err := Diff(a, b)
+	if err != nil {
+		return "", err
+	}
+
+	d := []byte(strings.Repeat(".", a.Len()))
+	for _, i := range pos {
+		d[a.Len()-1-i] = '^'
+	}
+
+	return string(d), nil
+}
+
+// Contains は，otherの1ビットがすべてsでも1であるか（s ⊇ other）を返します．
+// 長さが異なる場合，短い方のSpectrumの上位ビットは0として扱われます．
+func (s *Spectrum) Contains(other *Spectrum) bool {
+	return AndNot(other, s).Sign() == 0
+}
+
+// IsSubset は，sの1ビットがすべてotherでも1であるか（s ⊆ other）を返します．
+// 長さが異なる場合，短い方のSpectrumの上位ビットは0として扱われます．
+func (s *Spectrum) IsSubset(other *Spectrum) bool {
+	return other.Contains(s)
+}
+
+// Intersects は，sとotherに共通する1ビットが存在するかを返します．
+// 長さが異なる場合，短い方のSpectrumの上位ビットは0として扱われます．
+func (s *Spectrum) Intersects(other *Spectrum) bool {
+	return And(s, other).Sign() != 0
+}
+
+// IsDisjoint は，sとotherに共通する1ビットが存在しないかを返します．
+// 長さが異なる場合，短い方のSpectrumの上位ビットは0として扱われます．
+func (s *Spectrum) IsDisjoint(other *Spectrum) bool {
+	return !s.Intersects(other)
+}
+
+// Overlap は，2つのSpectrumの両方で1のビットの数 |A∩B| を返します．
+// Jaccard，Dice，Cosineの分子に相当し，AND比較の結果を確保せずに数えます．長さが異なる場合はエラーを返します．
+func Overlap(a, b *Spectrum) (uint, error) {
+	if a.Len() != b.Len() {
+		return 0, lengthMismatch(a.Len(), b.Len())
+	}
+
+	aw, bw := a.bitVector.Bits(), b.bitVector.Bits()
+	if len(bw) < len(aw) {
+		aw = aw[:len(bw)]
+	}
+
+	var count uint
+	for i, w := range aw {
+		count += uint(bits.OnesCount(uint(w & bw[i])))
+	}
+
+	return count, nil
+}
+
+// Jaccard は，2つのSpectrumのJaccard係数 |A∩B| / |A∪B| を返します．
+// 両方のSpectrumの1ビット数が0の場合は0を返し，長さが異なる場合はエラーを返します．
+func Jaccard(a, b *Spectrum) (float64, error) {
+	if a.Len() != b.Len() {
+		return 0, lengthMismatch(a.Len(), b.Len())
+	}
+
+	union := onesCount(Or(a, b))
+	if union == 0 {
+		return 0, nil
+	}
+
+	return float64(onesCount(And(a, b))) / float64(union), nil
+}
+
+// Dice は，2つのSpectrumのDice係数 2|A∩B| / (|A|+|B|) を返します．
+// 両方のSpectrumの1ビット数が0の場合は0を返し，長さが異なる場合はエラーを返します．
+func Dice(a, b *Spectrum) (float64, error) {
+	if a.Len() != b.Len() {
+		return 0, lengthMismatch(a.Len(), b.Len())
+	}
+
+	sum := onesCount(a.bitVector) + onesCount(b.bitVector)
+	if sum == 0 {
+		return 0, nil
+	}
+
+	return 2 * float64(onesCount(And(a, b))) / float64(sum), nil
+}
+
+// Cosine は，2つのSpectrumを2値ベクトルとみなしたコサイン類似度 |A∩B| / (√|A|・√|B|) を返します．
+// いずれかのSpectrumの1ビット数が0の場合は0を返し，長さが異なる場合はエラーを返します．
+func Cosine(a, b *Spectrum) (float64, error) {
+	if a.Len() != b.Len() {
+		return 0, lengthMismatch(a.Len(), b.Len())
+	}
+
+	na, nb := onesCount(a.bitVector), onesCount(b.bitVector)
+	if na == 0 || nb == 0 {
+		return 0, nil
+	}
+
+	return float64(onesCount(And(a, b))) / (math.Sqrt(float64(na)) * math.Sqrt(float64(nb))), nil
+}
+
+// Tanimoto は，2つのSpectrumのTanimoto係数を返します．
+// 2値ベクトルに対するTanimoto係数はJaccard係数と等しいため，Jaccardと同じ結果を返します．
+func Tanimoto(a, b *Spectrum) (float64, error) {
+	return Jaccard(a, b)
+}
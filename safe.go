@@ -0,0 +1,192 @@
+package spectrum
+
+import (
+	"math/big"
+	"sync"
+)
+
+// --- SafeSpectrum ---
+//
+// Spectrum は単一のgoroutineでの性能を優先して排他制御を行いません．
+// 複数のgoroutineで共有する場合は SafeSpectrum を使用します．
+
+// SafeSpectrum は，sync.RWMutexでSpectrumを保護し，複数のgoroutineから安全に操作できる構造体です．
+// bitVectorを変更する操作と疑似乱数を使用する操作は書き込みロックを，それ以外の操作は読み込みロックを取得します．
+type SafeSpectrum struct {
+	mu sync.RWMutex
+	s  *Spectrum
+}
+
+// NewSafeSpectrum は，指定した長さのSafeSpectrumを宣言して返します．
+func NewSafeSpectrum(length uint) (*SafeSpectrum, error) {
+	s, err := NewSpectrum(length)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SafeSpectrum{s: s}, nil
+}
+
+// Spectrum は，保護しているSpectrumの複製を返します．
+func (ss *SafeSpectrum) Spectrum() *Spectrum {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	return ss.s.Copy()
+}
+
+// Do は，書き込みロックを取得した状態でfnを呼び出します．
+// fnに渡されたSpectrumをfnの外で参照してはいけません．
+func (ss *SafeSpectrum) Do(fn func(s *Spectrum)) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	fn(ss.s)
+}
+
+// Len は，bitVectorの長さを返します．
+func (ss *SafeSpectrum) Len() int {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	return ss.s.Len()
+}
+
+// OnesCount は，1ビット数（hamming-weight）を返します．
+func (ss *SafeSpectrum) OnesCount() uint {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	return ss.s.OnesCount()
+}
+
+// TestBit は，bitVectorのiビット目が1であるかを返します．
+func (ss *SafeSpectrum) TestBit(i int) (bool, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	return ss.s.TestBit(i)
+}
+
+// BigInt は，bitVectorを10進数のbig.Int型で返します．
+func (ss *SafeSpectrum) BigInt() *big.Int {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	return ss.s.BigInt()
+}
+
+// Bit は，bitVectorを2進数表記の文字列で返します．
+func (ss *SafeSpectrum) Bit() string {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	return ss.s.Bit()
+}
+
+// Hex は，bitVectorを16進数表記の文字列で返します．
+func (ss *SafeSpectrum) Hex() string {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	return ss.s.Hex()
+}
+
+// Set は，bitVectorに値xを設定します．
+func (ss *SafeSpectrum) Set(x *big.Int) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	_, err := ss.s.Set(x)
+	return err
+}
+
+// SetUint64 は，bitVectorに値xを設定します．
+func (ss *SafeSpectrum) SetUint64(x uint64) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	_, err := ss.s.SetUint64(x)
+	return err
+}
+
+// SetString は，bitVectorに文字列で表現される値xを設定します．
+func (ss *SafeSpectrum) SetString(str string, base int) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	_, err := ss.s.SetString(str, base)
+	return err
+}
+
+// SetBit は，bitVectorのiビット目を1に設定します．
+func (ss *SafeSpectrum) SetBit(i int) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	return ss.s.SetBit(i)
+}
+
+// ClearBit は，bitVectorのiビット目を0に設定します．
+func (ss *SafeSpectrum) ClearBit(i int) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	return ss.s.ClearBit(i)
+}
+
+// ToggleBit は，bitVectorのiビット目を反転します．
+func (ss *SafeSpectrum) ToggleBit(i int) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	return ss.s.ToggleBit(i)
+}
+
+// Clear は，bitVectorのすべてのビットを0にします．
+func (ss *SafeSpectrum) Clear() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	ss.s.Clear()
+}
+
+// Fill は，Spectrumの長さのすべてのビットを1にします．
+func (ss *SafeSpectrum) Fill() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	ss.s.Fill()
+}
+
+// AdjustOnesCount は，指定した1ビット数になるまでビットフラグを増減させます．
+func (ss *SafeSpectrum) AdjustOnesCount(n uint) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	ss.s.AdjustOnesCount(n)
+}
+
+// Seed は，Spectrumが扱う疑似乱数のSeed値を変更します．
+func (ss *SafeSpectrum) Seed(seed int64) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	ss.s.Seed(seed)
+}
+
+// Uint64n は，指定した1ビット数を持つbitVectorをuint64で返します．フラグ位置はランダムです．
+func (ss *SafeSpectrum) Uint64n(n uint) uint64 {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	return ss.s.Uint64n(n)
+}
+
+// BigIntn は，指定した1ビット数を持つbitVectorをbig.Int型で返します．フラグ位置はランダムです．
+func (ss *SafeSpectrum) BigIntn(n uint) *big.Int {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	return ss.s.BigIntn(n)
+}
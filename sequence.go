@@ -0,0 +1,94 @@
+package spectrum
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// --- sequence （系列の生成と解析） ---
+
+// LFSRNext は，Spectrumを線形帰還シフトレジスタ（LFSR）とみなして1ステップ進めた新しいSpectrumを返します．
+// 帰還ビットはtapsで指定した位置のビットのXORであり，bitVectorを1ビット右シフトした後に最上位ビットに挿入されます．
+// tapsに範囲外の位置が含まれる場合はエラーを返します．
+func (s *Spectrum) LFSRNext(taps []int) (*Spectrum, error) {
+	var fb uint
+	for _, t := range taps {
+		if err := s.checkIndex(t); err != nil {
+			return nil, fmt.Errorf("%w: tap position %d is out of [0, %d)", ErrOutOfRange, t, s.length)
+		}
+		fb ^= s.bitVector.Bit(t)
+	}
+
+	b := s.BigInt()
+	b.Rsh(b, 1).SetBit(b, s.length-1, fb)
+
+	ns := s.clone()
+	ns.Set(b)
+	return ns, nil
+}
+
+// CAStep は，Spectrumを環状のセル列とみなし，Wolframの基本セル・オートマトンのruleを1ステップ適用した新しいSpectrumを返します．
+// 各セルの左隣は1つ上位のビット，右隣は1つ下位のビットであり，両端はRsh/Lshと同様に循環します．
+// (左, 自身, 右)を3ビットの値とみなしたとき，ruleのその値のビットが次の状態となります．
+func (s *Spectrum) CAStep(rule uint8) *Spectrum {
+	ns := s.clone()
+	for i := 0; i < s.length; i++ {
+		l := s.bitVector.Bit((i + 1) % s.length)
+		c := s.bitVector.Bit(i)
+		r := s.bitVector.Bit((i + s.length - 1) % s.length)
+		ns.bitVector.SetBit(ns.bitVector, i, uint(rule>>(l<<2|c<<1|r))&1)
+	}
+
+	ns.recount()
+	return ns
+}
+
+// Autocorrelation は，bitVectorとそれをshiftビット循環右シフト（Rsh）した系列との自己相関
+// （一致するビット数 - 一致しないビット数）を返します．shiftが負の場合は循環左シフトとなります．
+// 最大周期のLFSRが出力する系列（M系列）では，shiftが周期の倍数でない限り-1となります．
+func (s *Spectrum) Autocorrelation(shift int) int {
+	d := onesCount(Xor(s, Rotate(s, -shift)))
+
+	return s.length - 2*int(d)
+}
+
+// Period は，bitVectorをpビット循環シフトした系列が元の系列と一致する最小の正の整数p（周期）を返します．
+// Spectrumの長さより小さい周期が存在しない場合は，Spectrumの長さを返します．
+// 循環シフトの周期は長さの約数となるため，約数のみを検査します．ex. 0b101010 -> 2
+func (s *Spectrum) Period() int {
+	for p := 1; p < s.length; p++ {
+		if s.length%p == 0 && Rotate(s, p).Equal(s) {
+			return p
+		}
+	}
+
+	return s.length
+}
+
+// BlockEntropy は，最下位ビットからblockSizeビットごとに区切ったブロックの値の出現頻度に基づくShannonエントロピー（ビット）を返します．
+// Spectrumの長さがblockSizeで割り切れない場合，最上位の端数ブロックは集計に含めません．
+// blockSizeが0以下の場合，またはSpectrumの長さより大きい場合はエラーを返します．
+func (s *Spectrum) BlockEntropy(blockSize int) (float64, error) {
+	if blockSize <= 0 || s.length < blockSize {
+		return 0, fmt.Errorf("%w: block size %d is out of [1, %d]", ErrOutOfRange, blockSize, s.length)
+	}
+
+	n := s.length / blockSize
+	// ブロックの値は，blockSizeが64を超える場合にも対応できるよう最小長のバイト列をキーとして集計します．
+	freq := map[string]int{}
+	m := mask(blockSize)
+	b := big.NewInt(0)
+	for i := 0; i < n; i++ {
+		b.Rsh(s.bitVector, uint(i*blockSize))
+		freq[string(b.And(b, m).Bytes())]++
+	}
+
+	var h float64
+	for _, c := range freq {
+		p := float64(c) / float64(n)
+		h -= p * math.Log2(p)
+	}
+
+	return h, nil
+}
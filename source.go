@@ -0,0 +1,70 @@
+package spectrum
+
+import (
+	"math/rand"
+	"sync/atomic"
+)
+
+// --- random source （疑似乱数源） ---
+
+// countingSource は，最後に設定したSeed値とそれ以降の生成回数を記録するrand.Source64です．
+// math/randの疑似乱数源は内部状態を公開しないため，CloneRandはこの記録から状態を再現します．
+type countingSource struct {
+	// forks は，deriveを呼び出した回数です．疑似乱数の系列には含まれません．
+	// 32ビット環境でもsync/atomicで扱えるよう，先頭に配置します．
+	forks uint64
+
+	src  rand.Source64
+	seed int64
+	n    uint64
+}
+
+// newCountingSource は，Seed値seedで初期化したcountingSourceを返します．
+func newCountingSource(seed int64) *countingSource {
+	return &countingSource{src: rand.NewSource(seed).(rand.Source64), seed: seed}
+}
+
+// Int63 は，rand.Sourceを実装します．
+func (c *countingSource) Int63() int64 {
+	c.n++
+	return c.src.Int63()
+}
+
+// Uint64 は，rand.Source64を実装します．
+func (c *countingSource) Uint64() uint64 {
+	c.n++
+	return c.src.Uint64()
+}
+
+// Seed は，rand.Sourceを実装します．生成回数とderiveの呼び出し回数は0に戻ります．
+func (c *countingSource) Seed(seed int64) {
+	c.src.Seed(seed)
+	c.seed, c.n = seed, 0
+	atomic.StoreUint64(&c.forks, 0)
+}
+
+// derive は，Seed値，生成回数，deriveの呼び出し回数から，系列を進めずに複製先のSeed値を導出します（SplitMix64）．
+// 呼び出し回数は不可分に加算されるため，同じ状態から続けて複製しても異なるSeed値となり，
+// 複数のgoroutineから呼び出すこともできます．Seedを設定した後の複製は再現可能です．
+func (c *countingSource) derive() int64 {
+	k := atomic.AddUint64(&c.forks, 1)
+	z := uint64(c.seed) + (c.n+1)*0x9E3779B97F4A7C15
+	z ^= k * 0xD6E8FEB86659FD93
+	z = (z ^ z>>30) * 0xBF58476D1CE4E5B9
+	z = (z ^ z>>27) * 0x94D049BB133111EB
+
+	return int64(z ^ z>>31)
+}
+
+// clone は，同じSeed値から同じ回数だけ系列を進めた，状態の等しいcountingSourceを返します．
+// Int63とUint64はいずれも系列を1回分進めるため，Uint64のみで再現できます．
+// deriveの呼び出し回数も引き継ぐため，複製後のCopyの結果も複製元と一致します．
+func (c *countingSource) clone() *countingSource {
+	nc := newCountingSource(c.seed)
+	nc.forks = atomic.LoadUint64(&c.forks)
+	for ; nc.n < c.n; nc.n++ {
+		nc.src.Uint64()
+	}
+
+	return nc
+}
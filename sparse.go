@@ -0,0 +1,196 @@
+package spectrum
+
+import (
+	"fmt"
+	"sort"
+)
+
+// --- SparseSpectrum ---
+//
+// SparseSpectrum は，1ビットの位置を昇順のスライスで保持する疎なビット配列です．
+// 1ビットの割合が極めて低い長大なビット配列において，メモリ使用量と計算量を1ビット数に比例させます．
+
+// BitVector は，SpectrumとSparseSpectrumに共通するビット配列の操作を定義するインターフェースです．
+type BitVector interface {
+	Len() int
+	OnesCount() uint
+	TestBit(i int) (bool, error)
+	SetBit(i int) error
+	ClearBit(i int) error
+	ToggleBit(i int) error
+	Positions() []int
+}
+
+var (
+	_ BitVector = (*Spectrum)(nil)
+	_ BitVector = (*SparseSpectrum)(nil)
+)
+
+// SparseSpectrum は，1ビットの位置の集合としてspectrum情報を保持する構造体です．
+type SparseSpectrum struct {
+	pos    []int
+	length int
+}
+
+// NewSparseSpectrum は，指定した長さのSparseSpectrumを宣言して返します．
+// lengthが0の場合はエラーを返します．MaxLengthによる上限はありません．
+func NewSparseSpectrum(length uint) (*SparseSpectrum, error) {
+	if length == 0 {
+		return nil, fmt.Errorf("%w: NewSparseSpectrum: length must be greater than 0, got %d", ErrInvalidLength, length)
+	}
+
+	return &SparseSpectrum{pos: []int{}, length: int(length)}, nil
+}
+
+// Sparse は，Spectrumと同じ値を持つSparseSpectrumを返します．
+func (s *Spectrum) Sparse() *SparseSpectrum {
+	return &SparseSpectrum{pos: s.Positions(), length: s.length}
+}
+
+// Dense は，SparseSpectrumと同じ値を持つSpectrumを返します．
+// 長さがMaxLengthを超える場合はエラーを返します．
+func (ss *SparseSpectrum) Dense() (*Spectrum, error) {
+	s, err := NewSpectrum(uint(ss.length))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, i := range ss.pos {
+		s.bitVector.SetBit(s.bitVector, i, 1)
+	}
+
+	return s, nil
+}
+
+// Len は，ビット配列の長さを返します．
+func (ss *SparseSpectrum) Len() int {
+	return ss.length
+}
+
+// OnesCount は，1ビット数（hamming-weight）を返します．
+func (ss *SparseSpectrum) OnesCount() uint {
+	return uint(len(ss.pos))
+}
+
+// TestBit は，iビット目が1であるかを返します．
+func (ss *SparseSpectrum) TestBit(i int) (bool, error) {
+	if err := ss.checkIndex(i); err != nil {
+		return false, err
+	}
+
+	_, ok := ss.search(i)
+	return ok, nil
+}
+
+// SetBit は，iビット目を1に設定します．
+func (ss *SparseSpectrum) SetBit(i int) error {
+	if err := ss.checkIndex(i); err != nil {
+		return err
+	}
+
+	if k, ok := ss.search(i); !ok {
+		ss.pos = append(ss.pos, 0)
+		copy(ss.pos[k+1:], ss.pos[k:])
+		ss.pos[k] = i
+	}
+
+	return nil
+}
+
+// ClearBit は，iビット目を0に設定します．
+func (ss *SparseSpectrum) ClearBit(i int) error {
+	if err := ss.checkIndex(i); err != nil {
+		return err
+	}
+
+	if k, ok := ss.search(i); ok {
+		ss.pos = append(ss.pos[:k], ss.pos[k+1:]...)
+	}
+
+	return nil
+}
+
+// ToggleBit は，iビット目を反転します．
+func (ss *SparseSpectrum) ToggleBit(i int) error {
+	if ok, err := ss.TestBit(i); err != nil {
+		return err
+	} else if ok {
+		return ss.ClearBit(i)
+	}
+
+	return ss.SetBit(i)
+}
+
+// Positions は，1ビットの位置を昇順に並べたスライスを返します．
+func (ss *SparseSpectrum) Positions() []int {
+	return append([]int{}, ss.pos...)
+}
+
+// And は，2つのSparseSpectrumをAND比較したSparseSpectrumを返します．
+// 2つのSparseSpectrumの長さが異なる場合はエラーを返します．
+func (ss *SparseSpectrum) And(other *SparseSpectrum) (*SparseSpectrum, error) {
+	if ss.length != other.length {
+		return nil, lengthMismatch(ss.length, other.length)
+	}
+
+	pos := []int{}
+	for i, j := 0, 0; i < len(ss.pos) && j < len(other.pos); {
+		switch {
+		case ss.pos[i] < other.pos[j]:
+			i++
+		case other.pos[j] < ss.pos[i]:
+			j++
+		default:
+			pos = append(pos, ss.pos[i])
+			i++
+			j++
+		}
+	}
+
+	return &SparseSpectrum{pos: pos, length: ss.length}, nil
+}
+
+// Or は，2つのSparseSpectrumをOR比較したSparseSpectrumを返します．
+// 2つのSparseSpectrumの長さが異なる場合はエラーを返します．
+func (ss *SparseSpectrum) Or(other *SparseSpectrum) (*SparseSpectrum, error) {
+	if ss.length != other.length {
+		return nil, lengthMismatch(ss.length, other.length)
+	}
+
+	pos := make([]int, 0, len(ss.pos)+len(other.pos))
+	i, j := 0, 0
+	for i < len(ss.pos) && j < len(other.pos) {
+		switch {
+		case ss.pos[i] < other.pos[j]:
+			pos = append(pos, ss.pos[i])
+			i++
+		case other.pos[j] < ss.pos[i]:
+			pos = append(pos, other.pos[j])
+			j++
+		default:
+			pos = append(pos, ss.pos[i])
+			i++
+			j++
+		}
+	}
+	pos = append(pos, ss.pos[i:]...)
+	pos = append(pos, other.pos[j:]...)
+
+	return &SparseSpectrum{pos: pos, length: ss.length}, nil
+}
+
+// search は，iの挿入位置と，iが既に含まれているかを返します．
+func (ss *SparseSpectrum) search(i int) (int, bool) {
+	k := sort.SearchInts(ss.pos, i)
+
+	return k, k < len(ss.pos) && ss.pos[k] == i
+}
+
+// checkIndex は，iがビット配列の長さの範囲内にあるかを検査します．
+func (ss *SparseSpectrum) checkIndex(i int) error {
+	if i < 0 || ss.length <= i {
+		return fmt.Errorf("%w: index %d is out of [0, %d)", ErrOutOfRange, i, ss.length)
+	}
+
+	return nil
+}
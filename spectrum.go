@@ -3,11 +3,13 @@
 package spectrum
 
 import (
-	"errors"
 	"fmt"
+	"hash/fnv"
+	"math"
 	"math/big"
 	"math/bits"
 	"math/rand"
+	"strings"
 	"time"
 )
 
@@ -24,86 +26,620 @@ type Spectrum struct {
 	bitVector *big.Int
 	length    int
 	rnd       *rand.Rand
+	src       *countingSource
+
+	// ones は，bitVectorを変更する操作が更新するOnesCountの結果のキャッシュです．cachedがfalseの場合は無効です．
+	ones   uint
+	cached bool
 }
 
+// MaxLength は，NewSpectrumで宣言できるSpectrumの長さの上限です．
+// 意図しない巨大なメモリ確保を防ぐためのもので，必要に応じて変更できます．
+var MaxLength uint = 1 << 24
+
 // NewSpectrum は，Spectrumインターフェースを満たす構造体を宣言して返します．
+// lengthが0またはMaxLengthを超える場合はエラーを返します．
 func NewSpectrum(length uint) (*Spectrum, error) {
-	var err error
+	if length == 0 {
+		return nil, fmt.Errorf("%w: NewSpectrum: length must be greater than 0, got %d", ErrInvalidLength, length)
+	}
+	if MaxLength < length {
+		return nil, fmt.Errorf("%w: NewSpectrum: length must not exceed MaxLength(%d), got %d", ErrInvalidLength, MaxLength, length)
+	}
 
+	src := newCountingSource(time.Now().UnixNano())
 	return &Spectrum{
 		bitVector: big.NewInt(0),
 		length:    int(length),
-		rnd:       rand.New(rand.NewSource(time.Now().UnixNano())),
-	}, err
+		rnd:       rand.New(src),
+		src:       src,
+	}, nil
+}
+
+// NewSpectrumFromString は，文字列で表現される値strを設定したSpectrumを宣言して返します．
+// 進数はプレフィックス"0x"(16進数)，"0b"(2進数)，"0o"(8進数)から判定し，プレフィックスがない場合は10進数として扱います．
+func NewSpectrumFromString(str string, length uint) (*Spectrum, error) {
+	base := 10
+	if 2 <= len(str) {
+		switch strings.ToLower(str[:2]) {
+		case "0x":
+			base = 16
+		case "0b":
+			base = 2
+		case "0o":
+			base = 8
+		}
+	}
+	if base != 10 {
+		str = str[2:]
+	}
+
+	s, err := NewSpectrum(length)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.SetString(str, base)
+}
+
+// NewSpectrumFromBytes は，ビッグエンディアンのバイト列bを設定したSpectrumを宣言して返します．
+// 先頭の0バイトは無視され，有効なビット数がlengthを超える場合はエラーを返します．
+func NewSpectrumFromBytes(b []byte, length uint) (*Spectrum, error) {
+	s, err := NewSpectrum(length)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.SetBytes(b)
+}
+
+// OneHot は，indexビット目のみが1の長さlengthのSpectrumを宣言して返します．
+// indexがlength以上の場合はエラーを返します．
+func OneHot(length, index uint) (*Spectrum, error) {
+	s, err := NewSpectrum(length)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.SetBit(int(index)); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// OneCold は，indexビット目のみが0の長さlengthのSpectrumを宣言して返します．
+// indexがlength以上の場合はエラーを返します．
+func OneCold(length, index uint) (*Spectrum, error) {
+	s, err := OneHot(length, index)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Not(), nil
+}
+
+// Mask は，[lo, hi)の範囲のビットのみが1の長さlengthのSpectrumを宣言して返します．
+// hiは範囲に含まれません．lo > hiの場合，またはhiがlengthを超える場合はエラーを返します．
+// ex. Mask(8, 2, 5) -> 0b00011100
+func Mask(length, lo, hi uint) (*Spectrum, error) {
+	s, err := NewSpectrum(length)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.SetRange(int(lo), int(hi)); err != nil {
+		return nil, err
+	}
+
+	return s, nil
 }
 
 // Copy は，Spectrumを複製します．
+// 複製先の疑似乱数は，複製元に最後に設定したSeed値，それ以降の生成回数，複製した回数から導出したSeed値で初期化されるため，
+// 続けて複製したSpectrumどうしは異なる系列を生成し，Seedを設定した後のCopyの結果は再現可能です．
+// 複製元の疑似乱数の系列は変化しません．
 func (s *Spectrum) Copy() *Spectrum {
-	ns, _ := NewSpectrum(uint(s.length))
-	ns.Set(s.bitVector)
+	return s.clone()
+}
+
+// CopyWithSeed は，疑似乱数を指定したSeed値で初期化してSpectrumを複製します．
+// 複製元の疑似乱数の系列は変化しません．
+func (s *Spectrum) CopyWithSeed(seed int64) *Spectrum {
+	src := newCountingSource(seed)
+
+	return &Spectrum{
+		bitVector: s.BigInt(),
+		length:    s.length,
+		rnd:       rand.New(src),
+		src:       src,
+	}
+}
+
+// clone は，演算結果を構築するためにsを複製します．
+// sの疑似乱数から値を生成しないため，読み取りのみの演算でsの系列が進むことはありません．
+func (s *Spectrum) clone() *Spectrum {
+	return s.CopyWithSeed(s.src.derive())
+}
+
+// scratch は，bitVectorを複製し，疑似乱数をsと共有する作業用のSpectrumを返します．
+// Uint64nなど，sの疑似乱数の系列を用いて値を生成する演算で使用します．
+func (s *Spectrum) scratch() *Spectrum {
+	return &Spectrum{
+		bitVector: s.BigInt(),
+		length:    s.length,
+		rnd:       s.rnd,
+		src:       s.src,
+	}
+}
+
+// CloneRand は，疑似乱数の内部状態も含めてSpectrumを複製します．
+// Copyと異なり，複製先の疑似乱数は複製元と同じ系列の続きを生成するため，
+// 複製後に同じ操作を行えば複製元と複製先で同じ結果が得られます．複製元の疑似乱数の系列は変化しません．
+// math/randは内部状態を公開しないため，最後に設定したSeed値から生成回数分だけ系列を進めて状態を再現します．
+// そのため，計算量は最後のSeed以降の疑似乱数の生成回数に比例します．
+func (s *Spectrum) CloneRand() *Spectrum {
+	src := s.src.clone()
+	ns := &Spectrum{
+		bitVector: s.BigInt(),
+		length:    s.length,
+		rnd:       rand.New(src),
+		src:       src,
+		ones:      s.ones,
+		cached:    s.cached,
+	}
 
 	return ns
 }
 
+// Resize は，長さをnewLenに変更した新しいSpectrumを返します．
+// 伸長する場合は上位ビットが0で埋められ，短縮する場合は下位newLenビットが残ります．
+// 短縮によって失われる1ビット（newLenビット目以上）が存在する場合はエラーを返します．
+func (s *Spectrum) Resize(newLen uint) (*Spectrum, error) {
+	ns, err := NewSpectrum(newLen)
+	if err != nil {
+		return nil, err
+	}
+
+	return ns.Set(s.bitVector)
+}
+
+// TruncateLossy は，長さをnewLenに変更した新しいSpectrumを返します．
+// Resizeと異なり，newLenビット目以上のビットはエラーを返さずに破棄されます．
+func (s *Spectrum) TruncateLossy(newLen uint) (*Spectrum, error) {
+	ns, err := NewSpectrum(newLen)
+	if err != nil {
+		return nil, err
+	}
+
+	return ns.Set(big.NewInt(0).And(s.bitVector, mask(int(newLen))))
+}
+
+// AppendBit は，Spectrumの長さを1増やし，新たな最上位ビット（length-1ビット目）をbに設定します．
+// bが0以外の場合は1として扱います．新しいSpectrumを返さず，s自身の長さとbitVectorを変更します．
+// 最終的な長さが決まっていないSpectrumを逐次構築する用途を想定しています．
+// 長さがMaxLengthに達している場合は何もせずsを返します．伸長したかはLenで確認できます．
+func (s *Spectrum) AppendBit(b uint) *Spectrum {
+	if MaxLength <= uint(s.length) {
+		return s
+	}
+
+	s.length++
+	if b != 0 {
+		s.putBit(s.length-1, 1)
+	}
+
+	return s
+}
+
+// PrependBit は，bitVectorを1ビット左シフトして長さを1増やし，最下位ビット（0ビット目）をbに設定します．
+// bが0以外の場合は1として扱います．AppendBitと同様に，s自身の長さとbitVectorを変更します．
+// 長さがMaxLengthに達している場合は何もせずsを返します．
+func (s *Spectrum) PrependBit(b uint) *Spectrum {
+	if MaxLength <= uint(s.length) {
+		return s
+	}
+
+	s.bitVector.Lsh(s.bitVector, 1)
+	s.length++
+	if b != 0 {
+		s.putBit(0, 1)
+	}
+
+	return s
+}
+
 // Len は，bitVectorの長さを返します．
 func (s *Spectrum) Len() int {
 	return s.length
 }
 
+// ByteLen は，Spectrumの長さを表現するのに必要なバイト数ceil(length/8)を返します．
+// Bytesが返すバイト列の長さと一致します．
+func (s *Spectrum) ByteLen() int {
+	return (s.length + 7) / 8
+}
+
+// NibbleLen は，Spectrumの長さを表現するのに必要な4ビット（16進数の桁）数ceil(length/4)を返します．
+// Hexが返す文字列のプレフィックスを除いた桁数と一致します．
+func (s *Spectrum) NibbleLen() int {
+	return (s.length + 3) / 4
+}
+
 // OnesCount は，1ビット数（hamming-weight）を返します．
+// キャッシュはbitVectorを変更する操作の側で更新し，SetBit，ClearBit，ToggleBitなど1ビット単位の変更では差分で更新します．
+// OnesCount自身はキャッシュに書き込まないため，他の読み込み操作と同時に呼び出せます．
 func (s *Spectrum) OnesCount() uint {
+	if s.cached {
+		return s.ones
+	}
+
+	return onesCount(s.bitVector)
+}
+
+// recount は，bitVectorの変更に伴いOnesCountのキャッシュを再計算します．
+func (s *Spectrum) recount() {
+	s.ones, s.cached = onesCount(s.bitVector), true
+}
+
+// CountZeros は，Spectrumの長さの範囲内にある0ビット数を返します．
+// 常に Len() - OnesCount() と一致します．
+func (s *Spectrum) CountZeros() uint {
+	return uint(s.length) - s.OnesCount()
+}
+
+// Parity は，すべてのビットのXOR（1ビット数が偶数なら0，奇数なら1）を返します．
+func (s *Spectrum) Parity() int {
+	return int(s.OnesCount() & 1)
+}
+
+// Density は，Spectrumの長さに対する1ビット数の割合を返します．
+func (s *Spectrum) Density() float64 {
+	return float64(s.OnesCount()) / float64(s.length)
+}
+
+// IsZero は，すべてのビットが0であるかを返します．
+func (s *Spectrum) IsZero() bool {
+	return s.bitVector.Sign() == 0
+}
+
+// IsAllOnes は，Spectrumの長さのすべてのビットが1であるかを返します．
+func (s *Spectrum) IsAllOnes() bool {
+	return s.bitVector.Cmp(mask(s.length)) == 0
+}
+
+// IsOneHot は，1ビットがちょうど1つだけ存在するか（one-hot）を返します．
+func (s *Spectrum) IsOneHot() bool {
+	return s.OnesCount() == 1
+}
+
+// OneHotIndex は，one-hotのSpectrumが表す1ビットの位置（log2）を返します．
+// 1ビットが存在しない場合，または2つ以上存在する場合はエラーを返します．
+func (s *Spectrum) OneHotIndex() (int, error) {
+	if !s.IsOneHot() {
+		return 0, fmt.Errorf("%w: Spectrum with %d set bits is not one-hot", ErrInvalidArgument, s.OnesCount())
+	}
+
+	return s.LastSetBit(), nil
+}
+
+// onesCount は，big.Intの1ビット数を返します．
+func onesCount(x *big.Int) uint {
 	var count uint
-	for _, v := range s.bitVector.Bits() {
+	for _, v := range x.Bits() {
 		count += uint(bits.OnesCount(uint(v)))
 	}
 
 	return count
 }
 
+// Equal は，2つのSpectrumの長さとbitVectorの値がともに一致するかを返します．
+// 例えば，4ビットの0b0101と8ビットの0b00000101は一致しません．
+func (s *Spectrum) Equal(other *Spectrum) bool {
+	return s.length == other.length && s.EqualValue(other)
+}
+
+// EqualValue は，長さに関係なく2つのSpectrumのbitVectorの値が一致するかを返します．
+// 例えば，4ビットの0b0101と8ビットの0b00000101は一致します．
+func (s *Spectrum) EqualValue(other *Spectrum) bool {
+	return s.bitVector.Cmp(other.bitVector) == 0
+}
+
+// Cmp は，2つのSpectrumのbitVectorの値を比較し，s < otherなら-1，s == otherなら0，s > otherなら1を返します．
+// 値が等しい場合は長さを比較し，短い方を小さいとみなします．Cmpが0を返すのはEqualがtrueの場合に限られます．
+func (s *Spectrum) Cmp(other *Spectrum) int {
+	if c := s.bitVector.Cmp(other.bitVector); c != 0 {
+		return c
+	}
+
+	switch {
+	case s.length < other.length:
+		return -1
+	case other.length < s.length:
+		return 1
+	}
+
+	return 0
+}
+
+// Hash は，Spectrumの長さとbitVectorのバイト列（MarshalBinaryと同じ形式）から計算したFNV-1aハッシュ値を返します．
+// Equalがtrueとなる2つのSpectrumは同じハッシュ値を持ちます．
+func (s *Spectrum) Hash() uint64 {
+	b, _ := s.MarshalBinary()
+	h := fnv.New64a()
+	h.Write(b)
+
+	return h.Sum64()
+}
+
+// LeadingZeros は，Spectrumの長さを基準とした上位の連続する0ビット数を返します．
+// bitVectorが0の場合はSpectrumの長さを返します．
+func (s *Spectrum) LeadingZeros() int {
+	return s.length - s.bitVector.BitLen()
+}
+
+// TrailingZeros は，最下位ビットから連続する0ビット数を返します．
+// bitVectorが0の場合はSpectrumの長さを返します．
+func (s *Spectrum) TrailingZeros() int {
+	if s.bitVector.Sign() == 0 {
+		return s.length
+	}
+
+	return int(s.bitVector.TrailingZeroBits())
+}
+
 // AdjustOnesCount は，指定した1ビット数になるまでビットフラグを増減させます．
+// 増減させるビットの位置は，対象となる位置の集合をFisher-Yatesで部分的にシャッフルしてランダムに選ぶため，
+// 計算量はSpectrumの長さに比例します．nがSpectrumの長さを超える場合は，全ビットを1にします．
 func (s *Spectrum) AdjustOnesCount(n uint) *Spectrum {
-	var set uint = 1
-	if uint(s.length/2) < n {
-		s.bitVector.SetUint64(1)
-		s.bitVector.Lsh(s.bitVector, uint(s.length))
-		s.bitVector.Sub(s.bitVector, big.NewInt(1))
+	if uint(s.length) < n {
+		n = uint(s.length)
 	}
 
+	var set, from uint = 1, 0
 	oc := s.OnesCount()
-	if n < oc {
-		set = 0
+	diff := int(n) - int(oc)
+	if diff < 0 {
+		set, from, diff = 0, 1, -diff
 	}
 
-	for oc != n {
-		s.bitVector.SetBit(s.bitVector, s.rnd.Intn(s.length), set)
-		oc = s.OnesCount()
+	// 反転の対象となる位置（増やす場合は0ビット，減らす場合は1ビット）を列挙します．
+	pos := make([]int, 0, s.length)
+	for i := 0; i < s.length; i++ {
+		if s.bitVector.Bit(i) == from {
+			pos = append(pos, i)
+		}
 	}
 
+	for i := 0; i < diff; i++ {
+		j := i + s.rnd.Intn(len(pos)-i)
+		pos[i], pos[j] = pos[j], pos[i]
+		s.bitVector.SetBit(s.bitVector, pos[i], set)
+	}
+
+	s.ones, s.cached = n, true
 	return s
 }
 
+// Mutate は，互いに異なるk個のランダムな位置のビットを反転します（ビット反転突然変異）．
+// 1ビット数を指定した値に揃えるAdjustOnesCountと異なり，1ビット数は最大kだけ増減します．
+// kがSpectrumの長さを超える場合は，全ビットを反転します．
+func (s *Spectrum) Mutate(k uint) *Spectrum {
+	if uint(s.length) < k {
+		k = uint(s.length)
+	}
+
+	pos := make([]int, s.length)
+	for i := range pos {
+		pos[i] = i
+	}
+
+	for i := 0; i < int(k); i++ {
+		j := i + s.rnd.Intn(len(pos)-i)
+		pos[i], pos[j] = pos[j], pos[i]
+		s.bitVector.SetBit(s.bitVector, pos[i], s.bitVector.Bit(pos[i])^1)
+	}
+
+	s.recount()
+	return s
+}
+
+// Shuffle は，Fisher–Yatesのシャッフルによりビットの位置をランダムに並べ替えます．
+// 1ビット数を変更するAdjustOnesCountと異なり，1ビット数は変化せずビットの位置のみが移動します．
+// 疑似乱数にはSeedで設定した系列を使用します．
+func (s *Spectrum) Shuffle() *Spectrum {
+	for i := s.length - 1; 0 < i; i-- {
+		j := s.rnd.Intn(i + 1)
+		bi, bj := s.bitVector.Bit(i), s.bitVector.Bit(j)
+		if bi != bj {
+			s.bitVector.SetBit(s.bitVector, i, bj)
+			s.bitVector.SetBit(s.bitVector, j, bi)
+		}
+	}
+
+	return s
+}
+
+// RandomFill は，Spectrumの長さの各ビットを独立に確率pで1に設定します．
+// pは[0, 1]の範囲に切り詰められ，pが0以下の場合はClear，1以上の場合はFillと同じです．
+// pがNaNの場合はbitVectorを変更しません．これらの場合は疑似乱数を生成しません．
+// 範囲外のpをエラーとして扱う場合はRandomFillWeightsを使用してください．
+// 疑似乱数にはSeedで設定した系列を使用します．
+func (s *Spectrum) RandomFill(p float64) *Spectrum {
+	switch {
+	case math.IsNaN(p):
+		return s
+	case p <= 0:
+		s.Clear()
+	case 1 <= p:
+		s.Fill()
+	default:
+		s.bitVector.SetInt64(0)
+		for i := 0; i < s.length; i++ {
+			if s.rnd.Float64() < p {
+				s.bitVector.SetBit(s.bitVector, i, 1)
+			}
+		}
+		s.recount()
+	}
+
+	return s
+}
+
+// RandomFillWeights は，iビット目を独立に確率weights[i]で1に設定します．
+// weightsの長さがSpectrumの長さと異なる場合，または[0, 1]の範囲外の重みが含まれる場合は，bitVectorを変更せずにエラーを返します．
+// 疑似乱数にはSeedで設定した系列を使用します．
+func (s *Spectrum) RandomFillWeights(weights []float64) (*Spectrum, error) {
+	if len(weights) != s.length {
+		return nil, lengthMismatch(s.length, len(weights))
+	}
+	for i, w := range weights {
+		if !(0 <= w && w <= 1) {
+			return nil, fmt.Errorf("%w: weight %v at index %d is out of [0, 1]", ErrOutOfRange, w, i)
+		}
+	}
+
+	s.bitVector.SetInt64(0)
+	for i, w := range weights {
+		if s.rnd.Float64() < w {
+			s.bitVector.SetBit(s.bitVector, i, 1)
+		}
+	}
+
+	s.recount()
+	return s, nil
+}
+
+// Not は，bitVectorの全ビットを反転した新しいSpectrumを返します．
+// 反転はSpectrumの長さの範囲内に限定され，長さを超える上位ビットは0のままです．
+func (s *Spectrum) Not() *Spectrum {
+	ns := s.clone()
+	ns.bitVector.Xor(s.bitVector, mask(s.length))
+
+	return ns
+}
+
+// Reverse は，Spectrumの長さの範囲内でビット順序を反転した新しいSpectrumを返します．
+// iビット目は(length-1-i)ビット目に移動します．
+func (s *Spectrum) Reverse() *Spectrum {
+	ns := s.clone()
+	ns.bitVector.SetInt64(0)
+	for i := 0; i < s.bitVector.BitLen(); i++ {
+		if s.bitVector.Bit(i) == 1 {
+			ns.bitVector.SetBit(ns.bitVector, s.length-1-i, 1)
+		}
+	}
+
+	return ns
+}
+
+// Permute は，iビット目をsのmapping[i]ビット目とした新しいSpectrumを返します．
+// mappingの長さはSpectrumの長さと等しく，各要素は[0, length)の範囲である必要があります．
+// mappingは全単射である必要はなく，同じ位置を複数回参照する拡大転置にも使用できます．
+func (s *Spectrum) Permute(mapping []int) (*Spectrum, error) {
+	if len(mapping) != s.length {
+		return nil, lengthMismatch(s.length, len(mapping))
+	}
+
+	ns := s.clone()
+	ns.bitVector.SetInt64(0)
+	for i, j := range mapping {
+		if err := s.checkIndex(j); err != nil {
+			return nil, err
+		}
+		ns.bitVector.SetBit(ns.bitVector, i, s.bitVector.Bit(j))
+	}
+
+	ns.recount()
+	return ns, nil
+}
+
+// ToGray は，bitVectorを交番2進符号（Gray code）に変換した新しいSpectrumを返します．
+func (s *Spectrum) ToGray() *Spectrum {
+	ns := s.clone()
+	ns.bitVector.Rsh(s.bitVector, 1).Xor(ns.bitVector, s.bitVector)
+
+	return ns
+}
+
+// FromGray は，交番2進符号（Gray code）として解釈したbitVectorを2進数に復号した新しいSpectrumを返します．
+// ToGrayの逆変換です．
+func (s *Spectrum) FromGray() *Spectrum {
+	ns := s.clone()
+	t := big.NewInt(0)
+	for sh := uint(1); sh < uint(s.length); sh <<= 1 {
+		ns.bitVector.Xor(ns.bitVector, t.Rsh(ns.bitVector, sh))
+	}
+
+	return ns
+}
+
+// SwapBytes は，バイト順序を反転した新しいSpectrumを返します（エンディアン変換）．
+// Spectrumの長さが8の倍数でない場合はエラーを返します．
+// ex. 0x12345678 -> 0x78563412
+func (s *Spectrum) SwapBytes() (*Spectrum, error) {
+	b, err := s.alignedBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+
+	return s.clone().SetBytes(b)
+}
+
+// SwapNibbles は，各バイトの上位4ビットと下位4ビットを交換した新しいSpectrumを返します．
+// Spectrumの長さが8の倍数でない場合はエラーを返します．
+// ex. 0x12345678 -> 0x21436587
+func (s *Spectrum) SwapNibbles() (*Spectrum, error) {
+	b, err := s.alignedBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range b {
+		b[i] = b[i]<<4 | b[i]>>4
+	}
+
+	return s.clone().SetBytes(b)
+}
+
+// alignedBytes は，Spectrumの長さが8の倍数であることを検査し，Bytesの結果を返します．
+func (s *Spectrum) alignedBytes() ([]byte, error) {
+	if s.length%8 != 0 {
+		return nil, fmt.Errorf("%w: length must be a multiple of 8, got %d", ErrInvalidLength, s.length)
+	}
+
+	return s.Bytes(), nil
+}
+
 // Source は，Spectrumが扱う疑似乱数のSeed値を変更して再宣言します．
 func (s *Spectrum) Seed(seed int64) {
 	s.rnd.Seed(seed)
 }
 
-// Set は，bitVectorに値xを設定します．
+// Set は，bitVectorに値xを設定します．xが負の場合はエラーを返します．
 func (s *Spectrum) Set(x *big.Int) (*Spectrum, error) {
+	if x.Sign() < 0 {
+		return nil, fmt.Errorf("%w: got %s", ErrNegative, x)
+	}
 	if s.length < x.BitLen() {
-		return nil, errors.New("Error: bitVector is too big for length of Spectrum.")
+		return nil, fmt.Errorf("%w: %d bits for length %d", ErrTooLong, x.BitLen(), s.length)
 	}
 
 	s.bitVector.Set(x)
+	s.recount()
 	return s, nil
 }
 
 // SetUint64 は，bitVectorに値xを設定します．
 func (s *Spectrum) SetUint64(x uint64) (*Spectrum, error) {
-	if s.length < bits.Len(uint(x)) {
-		return nil, errors.New("Error: bitVector is too big for length of Spectrum.")
+	if s.length < bits.Len64(x) {
+		return nil, fmt.Errorf("%w: %d bits for length %d", ErrTooLong, bits.Len64(x), s.length)
 	}
 
 	s.bitVector.SetUint64(x)
+	s.recount()
 	return s, nil
 }
 
@@ -111,12 +647,95 @@ func (s *Spectrum) SetUint64(x uint64) (*Spectrum, error) {
 func (s *Spectrum) SetString(str string, base int) (*Spectrum, error) {
 	v, ok := big.NewInt(0).SetString(str, base)
 	if !ok {
-		return nil, errors.New("Error: Failed to convert string.")
+		return nil, fmt.Errorf("%w: %q is not a base-%d string", ErrParse, str, base)
 	}
 
 	return s.Set(v)
 }
 
+// Clear は，Spectrumの長さと疑似乱数の状態を保ったまま，bitVectorのすべてのビットを0にします．
+func (s *Spectrum) Clear() {
+	s.bitVector.SetInt64(0)
+	s.recount()
+}
+
+// Fill は，Spectrumの長さのすべてのビットを1にします．
+func (s *Spectrum) Fill() {
+	s.bitVector.Set(mask(s.length))
+	s.recount()
+}
+
+// SetBit は，bitVectorのiビット目を1に設定します．
+func (s *Spectrum) SetBit(i int) error {
+	if err := s.checkIndex(i); err != nil {
+		return err
+	}
+
+	s.putBit(i, 1)
+	return nil
+}
+
+// ClearBit は，bitVectorのiビット目を0に設定します．
+func (s *Spectrum) ClearBit(i int) error {
+	if err := s.checkIndex(i); err != nil {
+		return err
+	}
+
+	s.putBit(i, 0)
+	return nil
+}
+
+// ToggleBit は，bitVectorのiビット目を反転します．
+func (s *Spectrum) ToggleBit(i int) error {
+	if err := s.checkIndex(i); err != nil {
+		return err
+	}
+
+	s.putBit(i, s.bitVector.Bit(i)^1)
+	return nil
+}
+
+// TestBit は，bitVectorのiビット目が1であるかを返します．0ビット目は最下位ビットです．
+func (s *Spectrum) TestBit(i int) (bool, error) {
+	if err := s.checkIndex(i); err != nil {
+		return false, err
+	}
+
+	return s.bitVector.Bit(i) == 1, nil
+}
+
+// putBit は，bitVectorのiビット目をbに設定します．
+// 変更前のビットと比較してOnesCountのキャッシュを差分で更新するため，キャッシュが有効な間は全ビットを再計算しません．
+func (s *Spectrum) putBit(i int, b uint) {
+	if s.bitVector.Bit(i) == b {
+		return
+	}
+
+	s.bitVector.SetBit(s.bitVector, i, b)
+	switch {
+	case !s.cached:
+		s.recount()
+	case b == 1:
+		s.ones++
+	default:
+		s.ones--
+	}
+}
+
+// checkIndex は，iがSpectrumの長さの範囲内にあるかを検査します．
+func (s *Spectrum) checkIndex(i int) error {
+	if i < 0 || s.length <= i {
+		return fmt.Errorf("%w: index %d is out of [0, %d)", ErrOutOfRange, i, s.length)
+	}
+
+	return nil
+}
+
+// SetBytes は，ビッグエンディアンのバイト列bを符号なし整数としてbitVectorに設定します．
+func (s *Spectrum) SetBytes(b []byte) (*Spectrum, error) {
+	return s.Set(big.NewInt(0).SetBytes(b))
+}
+
 // IsUint64 は，bitVectorがuint64型で表現できるかを返します．
 func (s *Spectrum) IsUint64() bool {
 	return s.bitVector.IsUint64()
@@ -127,16 +746,52 @@ func (s *Spectrum) Uint64() uint64 {
 	return s.bitVector.Uint64()
 }
 
+// Uint64Checked は，bitVectorを10進数のuint64型で返します．uint64で表せない場合はエラーを返します．
+func (s *Spectrum) Uint64Checked() (uint64, error) {
+	if !s.IsUint64() {
+		return 0, fmt.Errorf("%w: %d bits cannot be represented as uint64", ErrTooLong, s.bitVector.BitLen())
+	}
+
+	return s.bitVector.Uint64(), nil
+}
+
 // BigInt は，bitVectorを10進数のbig.Int型で返します．
 func (s *Spectrum) BigInt() *big.Int {
 	return big.NewInt(0).Set(s.bitVector)
 }
 
+// Bytes は，bitVectorをceil(length/8)バイトの固定長のビッグエンディアンのバイト列で返します．
+// 上位の余りバイトは0で埋められます．
+func (s *Spectrum) Bytes() []byte {
+	return s.bitVector.FillBytes(make([]byte, s.ByteLen()))
+}
+
 // Bit は，bitVectorを2進数表記の文字列で返します．プレフィックに"0b"が追加されます．
 func (s *Spectrum) Bit() string {
 	return "0b" + fmt.Sprintf("%0*s", s.length, s.bitVector.Text(2))
 }
 
+// BitGrouped は，bitVectorを2進数表記の文字列で返します．プレフィックに"0b"が追加されます．
+// 最下位ビットからgroupビットごとに区切り文字sepが挿入されます．groupが0以下の場合はBitと同じです．
+// ex. BitGrouped(4, "_") -> 0b0000_1111
+func (s *Spectrum) BitGrouped(group int, sep string) string {
+	b := s.Bit()[2:]
+	if group <= 0 {
+		return "0b" + b
+	}
+
+	var sb strings.Builder
+	sb.WriteString("0b")
+	for i := 0; i < len(b); i++ {
+		if 0 < i && (len(b)-i)%group == 0 {
+			sb.WriteString(sep)
+		}
+		sb.WriteByte(b[i])
+	}
+
+	return sb.String()
+}
+
 // String は，bitVectorを指定した進数での文字列を返します．プレフィックスは追加されません．
 func (s *Spectrum) String(base int) string {
 	return s.bitVector.Text(base)
@@ -144,20 +799,72 @@ func (s *Spectrum) String(base int) string {
 
 // Hex は，bitVectorを16進数表記の文字列で返します．プレフィックスに"0x"が追加されます．
 func (s *Spectrum) Hex() string {
-	l := s.length / 4
-	if 0 < s.length%4 {
-		l++
+	return s.HexOpts(false, "0x")
+}
+
+// HexUpper は，bitVectorを大文字の16進数表記の文字列で返します．プレフィックスに"0x"が追加されます．
+func (s *Spectrum) HexUpper() string {
+	return s.HexOpts(true, "0x")
+}
+
+// HexOpts は，bitVectorを16進数表記の文字列で返します．
+// upperがtrueの場合は大文字で出力し，プレフィックスにはprefixが追加されます．
+// いずれの場合も，ceil(length/4)桁になるよう0で埋められます．
+func (s *Spectrum) HexOpts(upper bool, prefix string) string {
+	h := fmt.Sprintf("%0*s", s.NibbleLen(), s.bitVector.Text(16))
+	if upper {
+		h = strings.ToUpper(h)
+	}
+
+	return prefix + h
+}
+
+// Format は，fmt.Formatterを実装します．
+// 'b'，'o'，'x'，'X'はSpectrumの長さに応じた桁数まで0で埋めて出力し，
+// '#'フラグを指定した場合はそれぞれ"0b"，"0o"，"0x"，"0X"をプレフィックスとして追加します．
+// 'v'はBitと同じ形式で出力します．ex. fmt.Sprintf("%#x", s) == s.Hex()
+func (s Spectrum) Format(f fmt.State, verb rune) {
+	var base, digits int
+	var prefix string
+	switch verb {
+	case 'b', 'v':
+		base, digits, prefix = 2, s.length, "0b"
+	case 'o':
+		base, digits, prefix = 8, (s.length+2)/3, "0o"
+	case 'x', 'X':
+		base, digits, prefix = 16, s.NibbleLen(), "0x"
+	default:
+		fmt.Fprintf(f, "%%!%c(spectrum.Spectrum=%s)", verb, s.Bit())
+		return
+	}
+
+	str := fmt.Sprintf("%0*s", digits, s.bitVector.Text(base))
+	if verb == 'X' {
+		str, prefix = strings.ToUpper(str), "0X"
+	}
+	if verb == 'v' || f.Flag('#') {
+		str = prefix + str
 	}
 
-	return "0x" + fmt.Sprintf("%0*s", l, s.bitVector.Text(16))
+	fmt.Fprint(f, str)
 }
 
 // Uint64n は，指定した1ビット数を持つbitVectorをuint64で返します．フラグ位置はランダムです．uint64で表せない場合は未定義です．
+// フラグ位置の決定にはsの疑似乱数の系列を使用します．
 func (s *Spectrum) Uint64n(n uint) uint64 {
-	return s.Copy().AdjustOnesCount(n).Uint64()
+	return s.scratch().AdjustOnesCount(n).Uint64()
+}
+
+// mask は，下位lengthビットがすべて1のbig.Intを返します．
+func mask(length int) *big.Int {
+	m := big.NewInt(1)
+	m.Lsh(m, uint(length))
+
+	return m.Sub(m, big.NewInt(1))
 }
 
 // BigIntn は，指定した1ビット数を持つbitVectorをbig.Int型で返します．フラグ位置はランダムです．
+// フラグ位置の決定にはsの疑似乱数の系列を使用します．
 func (s *Spectrum) BigIntn(n uint) *big.Int {
-	return s.Copy().AdjustOnesCount(n).BigInt()
+	return s.scratch().AdjustOnesCount(n).BigInt()
 }
@@ -1,7 +1,18 @@
 package spectrum
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
 	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -12,6 +23,70 @@ const (
 	bits64 uint64 = 0xFFFFFFFFFFFFFFFF
 )
 
+func TestNewSpectrum(t *testing.T) {
+	t.Logf("Exec: NewSpectrum()")
+	if spctr, err := NewSpectrum(1); err != nil {
+		t.Fatal(err)
+	} else if spctr.Len() != 1 {
+		t.Errorf("Length expected %d, got %d", 1, spctr.Len())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: NewSpectrum()")
+	if spctr, err := NewSpectrum(0); err == nil || spctr != nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if spctr, err := NewSpectrum(MaxLength + 1); err == nil || spctr != nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestNewSpectrumFromString(t *testing.T) {
+	pattern := []struct {
+		str  string
+		want uint64
+	}{
+		{"0xff", 0xff},
+		{"0XFF", 0xff},
+		{"0b1010", 0xa},
+		{"0o17", 0xf},
+		{"255", 0xff},
+		{"010", 10},
+	}
+
+	t.Logf("Exec: NewSpectrumFromString()")
+	for _, p := range pattern {
+		if got, err := NewSpectrumFromString(p.str, 8); err != nil {
+			t.Fatal(err)
+		} else if got.Len() != 8 || got.Uint64() != p.want {
+			t.Errorf("Case(%s) expected %d, got %d", p.str, p.want, got.Uint64())
+		}
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: NewSpectrumFromString()")
+	for _, str := range []string{"0x1ff", "256", "0b2", "", "0x"} {
+		if _, err := NewSpectrumFromString(str, 8); err == nil {
+			t.Errorf("Case(%s): Error handling may not be appropriate.", str)
+		}
+	}
+}
+
+func TestNewSpectrumFromBytes(t *testing.T) {
+	t.Logf("Exec: NewSpectrumFromBytes()")
+	if got, err := NewSpectrumFromBytes([]byte{0x00, 0x00, 0x01, 0xFF}, 9); err != nil {
+		t.Fatal(err)
+	} else if got.Len() != 9 || got.Uint64() != 0x1FF {
+		t.Errorf("Expected 0x1ff of 9bits, got %v", got.Hex())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: NewSpectrumFromBytes()")
+	if _, err := NewSpectrumFromBytes([]byte{0x02, 0x00}, 9); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
 func TestLen(t *testing.T) {
 	spctr, _ := NewSpectrum(64)
 
@@ -22,6 +97,24 @@ func TestLen(t *testing.T) {
 	}
 }
 
+func TestByteLen(t *testing.T) {
+	pattern := []struct {
+		length           uint
+		byteLen, nibbles int
+	}{{1, 1, 1}, {4, 1, 1}, {5, 1, 2}, {8, 1, 2}, {9, 2, 3}, {64, 8, 16}, {65, 9, 17}}
+
+	t.Logf("Exec: ByteLen(), NibbleLen()")
+	for _, p := range pattern {
+		spctr, _ := NewSpectrum(p.length)
+		if got := spctr.ByteLen(); got != p.byteLen || got != len(spctr.Bytes()) {
+			t.Errorf("%dbits ByteLen() expected %d, got %d", p.length, p.byteLen, got)
+		}
+		if got := spctr.NibbleLen(); got != p.nibbles || got != len(spctr.Hex())-2 {
+			t.Errorf("%dbits NibbleLen() expected %d, got %d", p.length, p.nibbles, got)
+		}
+	}
+}
+
 func TestCopy(t *testing.T) {
 	spctr, _ := NewSpectrum(64)
 
@@ -61,6 +154,10 @@ func TestSet(t *testing.T) {
 		t.Error("Error handling may not be appropriate.")
 	}
 
+	if _, err := spctr.Set(big.NewInt(-1)); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+
 	t.Logf("Error handling: SetUint64()")
 	if _, err := spctr.SetUint64(bits64); err == nil {
 		t.Error("Error handling may not be appropriate.")
@@ -70,6 +167,26 @@ func TestSet(t *testing.T) {
 	if _, err := spctr.SetString("FFFFFFFFFFFFFFFF", 16); err == nil {
 		t.Error("Error handling may not be appropriate.")
 	}
+	if _, err := spctr.SetString("-1", 10); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestClearFill(t *testing.T) {
+	spctr, _ := NewSpectrum(13)
+	spctr.SetUint64(0x1A5)
+
+	t.Logf("Exec: Clear()")
+	spctr.Clear()
+	if got := spctr.OnesCount(); got != 0 || spctr.Len() != 13 {
+		t.Errorf("Expected zero of 13bits, got %v", spctr.Bit())
+	}
+
+	t.Logf("Exec: Fill()")
+	spctr.Fill()
+	if got := spctr.OnesCount(); got != 13 || spctr.Len() != 13 {
+		t.Errorf("Expected all ones of 13bits, got %v", spctr.Bit())
+	}
 }
 
 // --- Output ---
@@ -83,6 +200,20 @@ func TestGet(t *testing.T) {
 		t.Errorf("Uint64() expected %x, got %x", bits64, got)
 	}
 
+	t.Logf("Exec: Uint64Checked()")
+	if got, err := spctr.Uint64Checked(); err != nil {
+		t.Fatal(err)
+	} else if got != bits64 {
+		t.Errorf("Uint64Checked() expected %x, got %x", bits64, got)
+	}
+
+	t.Logf("Error handling: Uint64Checked()")
+	spctr128, _ := NewSpectrum(128)
+	spctr128.SetString("ffffffffffffffffffffffffffffffff", 16)
+	if _, err := spctr128.Uint64Checked(); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+
 	t.Logf("Exec: BigInt()")
 	spctr.SetUint64(bits32)
 	if got := spctr.BigInt(); got.Cmp(big.NewInt(bits32)) != 0 {
@@ -119,6 +250,78 @@ func TestGet(t *testing.T) {
 	}
 }
 
+func TestFormat(t *testing.T) {
+	spctr, _ := NewSpectrum(13)
+	spctr.SetUint64(0xAB)
+
+	pattern := []struct {
+		format string
+		want   string
+	}{
+		{"%b", "0000010101011"},
+		{"%#b", "0b0000010101011"},
+		{"%o", "00253"},
+		{"%#o", "0o00253"},
+		{"%x", "00ab"},
+		{"%#x", spctr.Hex()},
+		{"%X", "00AB"},
+		{"%#X", "0X00AB"},
+		{"%v", spctr.Bit()},
+	}
+
+	t.Logf("Exec: Format()")
+	for _, p := range pattern {
+		if got := fmt.Sprintf(p.format, spctr); got != p.want {
+			t.Errorf("Sprintf(%q) expected %s, got %s", p.format, p.want, got)
+		}
+		if got := fmt.Sprintf(p.format, *spctr); got != p.want {
+			t.Errorf("Sprintf(%q) by value expected %s, got %s", p.format, p.want, got)
+		}
+	}
+}
+
+func TestHexOpts(t *testing.T) {
+	spctr, _ := NewSpectrum(13)
+	spctr.SetUint64(0xAB)
+
+	t.Logf("Exec: HexUpper()")
+	if got := spctr.HexUpper(); got != "0x00AB" {
+		t.Errorf("Expected %s, got %s", "0x00AB", got)
+	}
+
+	t.Logf("Exec: HexOpts()")
+	if got := spctr.HexOpts(false, ""); got != "00ab" {
+		t.Errorf("Expected %s, got %s", "00ab", got)
+	}
+	if got := spctr.HexOpts(true, "#"); got != "#00AB" {
+		t.Errorf("Expected %s, got %s", "#00AB", got)
+	}
+}
+
+func TestBitGrouped(t *testing.T) {
+	pattern := []struct {
+		length uint
+		group  int
+		sep    string
+		want   string
+	}{
+		{8, 4, "_", "0b0000_1111"},
+		{10, 4, "_", "0b00_0000_1111"},
+		{8, 3, " ", "0b00 001 111"},
+		{8, 8, "_", "0b00001111"},
+		{8, 0, "_", "0b00001111"},
+	}
+
+	t.Logf("Exec: BitGrouped()")
+	for _, p := range pattern {
+		spctr, _ := NewSpectrum(p.length)
+		spctr.SetUint64(0x0F)
+		if got := spctr.BitGrouped(p.group, p.sep); got != p.want {
+			t.Errorf("Expected %s, got %s", p.want, got)
+		}
+	}
+}
+
 // --- bits ---
 
 func testOnesCount(t *testing.T, spctr *Spectrum, want uint) {
@@ -138,147 +341,3058 @@ func TestOnesCount(t *testing.T) {
 	testOnesCount(t, spctr, 32)
 }
 
-func TestAdjustOnesCount(t *testing.T) {
-	spctr, _ := NewSpectrum(64)
+func TestOnesCountCache(t *testing.T) {
+	spctr, _ := NewSpectrum(200)
+	other, _ := NewSpectrum(200)
+	spctr.Seed(1)
+	other.AdjustOnesCount(100)
 
-	t.Logf("Exec: AdjustOnesCount()")
-	spctr.AdjustOnesCount(8)
-	testOnesCount(t, spctr, 8)
+	ops := []func(){
+		func() { spctr.SetBit(spctr.rnd.Intn(200)) },
+		func() { spctr.ClearBit(spctr.rnd.Intn(200)) },
+		func() { spctr.ToggleBit(spctr.rnd.Intn(200)) },
+		func() { spctr.AdjustOnesCount(uint(spctr.rnd.Intn(201))) },
+		func() { spctr.SetUint64(spctr.rnd.Uint64()) },
+		func() { spctr.SetRange(10, 50) },
+		func() { spctr.ClearRange(30, 150) },
+		func() { spctr.XorWith(other) },
+		func() { spctr.OrWith(other) },
+		func() { spctr.AndWith(other) },
+		func() { spctr.Clear() },
+		func() { spctr.Fill() },
+	}
 
-	spctr.AdjustOnesCount(4)
-	testOnesCount(t, spctr, 4)
+	t.Logf("Exec: OnesCount() with cache")
+	for i := 0; i < 1000; i++ {
+		ops[spctr.rnd.Intn(len(ops))]()
+		if got, want := spctr.OnesCount(), onesCount(spctr.bitVector); got != want {
+			t.Fatalf("Cached OnesCount() expected %d, got %d", want, got)
+		}
+	}
+
+	t.Logf("Exec: concurrent OnesCount() without cache")
+	fresh := other.Not()
+	want := onesCount(fresh.bitVector)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				fresh.Density()
+				fresh.Positions()
+				Dice(fresh, other)
+				Cosine(other, fresh)
+			}
+		}()
+	}
+	wg.Wait()
+	if fresh.cached {
+		t.Errorf("Expected OnesCount() not to write the cache")
+	}
+	if got := fresh.OnesCount(); got != want {
+		t.Errorf("Expected %d, got %d", want, got)
+	}
 }
 
-// --- rand ---
+func TestOnesCountIncremental(t *testing.T) {
+	spctr, _ := NewSpectrum(300)
+	spctr.Seed(2)
+	spctr.AdjustOnesCount(150)
 
-func TestSeed(t *testing.T) {
-	var cp []int
-	spctr, _ := NewSpectrum(64)
+	ops := []func(i int){
+		func(i int) { spctr.SetBit(i) },
+		func(i int) { spctr.ClearBit(i) },
+		func(i int) { spctr.ToggleBit(i) },
+		func(i int) { spctr.SetBit(i + spctr.Len()) },
+	}
 
-	t.Logf("Exec: Seed()")
-	spctr.Seed(1)
-	for i := 0; i < 5; i++ {
-		cp = append(cp, spctr.rnd.Intn(10))
+	t.Logf("Exec: OnesCount() with single-bit operations")
+	for i := 0; i < 10000; i++ {
+		ops[spctr.rnd.Intn(len(ops))](spctr.rnd.Intn(spctr.Len()))
+		if !spctr.cached {
+			t.Fatalf("Expected OnesCount() cache to be kept after single-bit operation")
+		}
+		if got, want := spctr.OnesCount(), onesCount(spctr.bitVector); got != want {
+			t.Fatalf("Cached OnesCount() expected %d, got %d", want, got)
+		}
 	}
 
-	spctr.Seed(1)
-	for i := 0; i < 5; i++ {
-		if got := spctr.rnd.Intn(10); got != cp[i] {
-			t.Errorf("rand value expected %d, got %d", cp[i], got)
+	t.Logf("Exec: OnesCount() with AppendBit(), PrependBit()")
+	for i := 0; i < 200; i++ {
+		if spctr.rnd.Intn(2) == 0 {
+			spctr.AppendBit(uint(spctr.rnd.Intn(2)))
+		} else {
+			spctr.PrependBit(uint(spctr.rnd.Intn(2)))
+		}
+		if got, want := spctr.OnesCount(), onesCount(spctr.bitVector); got != want {
+			t.Fatalf("Cached OnesCount() expected %d, got %d", want, got)
 		}
 	}
 }
 
-// --- bitwise operation ---
+func BenchmarkOnesCountScan(b *testing.B) {
+	spctr, _ := NewSpectrum(4096)
+	spctr.AdjustOnesCount(2048)
 
-func TestAnd(t *testing.T) {
-	spctr32, _ := NewSpectrum(64)
-	spctr64, _ := NewSpectrum(64)
+	for i := 0; i < b.N; i++ {
+		spctr.ToggleBit(i % 4096)
+		for j := 0; j < 16; j++ {
+			onesCount(spctr.bitVector)
+		}
+	}
+}
 
-	spctr32.SetUint64(bits32)
-	spctr64.SetUint64(bits64)
+func BenchmarkOnesCountCached(b *testing.B) {
+	spctr, _ := NewSpectrum(4096)
+	spctr.AdjustOnesCount(2048)
 
-	t.Logf("Exec: And()")
-	if got := And(spctr64, spctr32); got.Cmp(big.NewInt(bits32)) != 0 {
-		t.Errorf("Expected %x, got %x", bits32, got)
+	for i := 0; i < b.N; i++ {
+		spctr.ToggleBit(i % 4096)
+		for j := 0; j < 16; j++ {
+			spctr.OnesCount()
+		}
 	}
 }
 
-func TestOr(t *testing.T) {
-	spctr32, _ := NewSpectrum(64)
-	spctr64, _ := NewSpectrum(64)
+func TestCountZeros(t *testing.T) {
+	spctr, _ := NewSpectrum(70)
 
-	spctr32.SetUint64(bits32)
-	spctr64.SetUint64(bits64)
+	t.Logf("Exec: CountZeros()")
+	if got := spctr.CountZeros(); got != 70 {
+		t.Errorf("Case(0x0) expected %d, got %d", 70, got)
+	}
 
-	t.Logf("Exec: Or()")
-	if got := Or(spctr64, spctr32); got.Cmp(big.NewInt(0).SetUint64(bits64)) != 0 {
-		t.Errorf("Expected %x, got %x", bits64, got)
+	spctr.SetUint64(bits32)
+	if got := spctr.CountZeros(); got != 38 {
+		t.Errorf("Case(0x%x) expected %d, got %d", bits32, 38, got)
 	}
 }
 
-func TestAndNot(t *testing.T) {
-	spctr32, _ := NewSpectrum(64)
-	spctr64, _ := NewSpectrum(64)
+func TestParity(t *testing.T) {
+	spctr, _ := NewSpectrum(70)
+	spctr.SetUint64(0xF0F0)
 
-	spctr32.SetUint64(bits32)
-	spctr64.SetUint64(bits64 - 1)
+	t.Logf("Exec: Parity()")
+	if got := spctr.Parity(); got != 0 {
+		t.Errorf("Case(0x%x) expected %d, got %d", spctr.bitVector, 0, got)
+	}
+	for i := 0; i < spctr.Len(); i++ {
+		want := spctr.Parity() ^ 1
+		spctr.ToggleBit(i)
+		if got := spctr.Parity(); got != want {
+			t.Errorf("Toggle(%d) expected %d, got %d", i, want, got)
+		}
+	}
+}
 
-	t.Logf("Exec: AndNot()")
-	want, _ := big.NewInt(0).SetString("00000001", 16)
-	if got := AndNot(spctr32, spctr64); got.Cmp(want) != 0 {
-		t.Errorf("Expected %x, got %x", want, got)
+func TestDensity(t *testing.T) {
+	spctr, _ := NewSpectrum(64)
+
+	t.Logf("Exec: Density()")
+	for _, want := range []float64{0, 0.5, 1.0} {
+		spctr.AdjustOnesCount(uint(want * 64))
+		if got := spctr.Density(); got != want {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
 	}
+}
 
-	want, _ = big.NewInt(0).SetString("ffffffff00000000", 16)
-	if got := AndNot(spctr64, spctr32); got.Cmp(want) != 0 {
-		t.Errorf("Expected %x, got %x", want, got)
+func TestPredicates(t *testing.T) {
+	spctr7, _ := NewSpectrum(7)
+	spctr64, _ := NewSpectrum(64)
+
+	pattern := []struct {
+		spctr   *Spectrum
+		x       uint64
+		zero    bool
+		allOnes bool
+		oneHot  bool
+	}{
+		{spctr7, 0, true, false, false},
+		{spctr7, 0x7f, false, true, false},
+		{spctr7, 0x40, false, false, true},
+		{spctr64, 0, true, false, false},
+		{spctr64, bits64, false, true, false},
+		{spctr64, bits32, false, false, false},
+		{spctr64, 1, false, false, true},
 	}
 
+	t.Logf("Exec: IsZero(), IsAllOnes(), IsOneHot()")
+	for _, p := range pattern {
+		p.spctr.SetUint64(p.x)
+		if got := p.spctr.IsZero(); got != p.zero {
+			t.Errorf("Case(0x%x) IsZero() expected %v, got %v", p.x, p.zero, got)
+		}
+		if got := p.spctr.IsAllOnes(); got != p.allOnes {
+			t.Errorf("Case(0x%x) IsAllOnes() expected %v, got %v", p.x, p.allOnes, got)
+		}
+		if got := p.spctr.IsOneHot(); got != p.oneHot {
+			t.Errorf("Case(0x%x) IsOneHot() expected %v, got %v", p.x, p.oneHot, got)
+		}
+	}
 }
 
-func TestXor(t *testing.T) {
-	spctr32, _ := NewSpectrum(64)
-	spctr64, _ := NewSpectrum(64)
+func TestOneHot(t *testing.T) {
+	t.Logf("Exec: OneHot(), OneCold()")
+	for _, i := range []uint{0, 5, 64, 99} {
+		hot, err := OneHot(100, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := hot.OneHotIndex(); hot.Len() != 100 || got != int(i) {
+			t.Errorf("OneHot(100, %d) expected index %d, got %d", i, i, got)
+		}
 
-	spctr32.SetUint64(bits32)
-	spctr64.SetUint64(bits64)
+		cold, err := OneCold(100, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := cold.TestBit(int(i)); got || cold.Len() != 100 || cold.OnesCount() != 99 {
+			t.Errorf("OneCold(100, %d) expected only bit %d to be clear, got %v", i, i, cold.Hex())
+		}
+	}
 
-	t.Logf("Exec: Xor()")
-	want, _ := big.NewInt(0).SetString("ffffffff00000000", 16)
-	if got := Xor(spctr64, spctr32); got.Cmp(want) != 0 {
-		t.Errorf("Expected %x, got %x", want, got)
+	// -- exception usecase --
+	t.Logf("Error handling: OneHot(), OneCold()")
+	if _, err := OneHot(8, 8); !errors.Is(err, ErrOutOfRange) {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if _, err := OneCold(8, 8); !errors.Is(err, ErrOutOfRange) {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if _, err := OneHot(0, 0); !errors.Is(err, ErrInvalidLength) {
+		t.Error("Error handling may not be appropriate.")
 	}
 }
 
-// --- shift operation ---
+func TestMask(t *testing.T) {
+	t.Logf("Exec: Mask()")
+	for _, p := range []struct {
+		length, lo, hi uint
+		want           string
+	}{
+		{8, 2, 5, "0b00011100"},
+		{8, 0, 8, "0b11111111"},
+		{8, 3, 3, "0b00000000"},
+		{4, 0, 1, "0b0001"},
+	} {
+		if got, err := Mask(p.length, p.lo, p.hi); err != nil {
+			t.Fatal(err)
+		} else if got.Bit() != p.want {
+			t.Errorf("Mask(%d, %d, %d) expected %v, got %v", p.length, p.lo, p.hi, p.want, got.Bit())
+		}
+	}
 
-func TestRsh(t *testing.T) {
-	spctr, _ := NewSpectrum(8)
+	t.Logf("Exec: AndWith() with Mask()")
+	spctr, _ := NewSpectrum(16)
+	spctr.SetUint64(0xABCD)
+	m, _ := Mask(16, 4, 12)
+	if err := spctr.AndWith(m.Not()); err != nil {
+		t.Fatal(err)
+	} else if spctr.Uint64() != 0xA00D {
+		t.Errorf("Expected %x, got %v", 0xA00D, spctr.Hex())
+	}
 
-	pattern := []string{
-		"11111111",
-		"10101010",
-		"01010101",
-		"00000000",
+	// -- exception usecase --
+	t.Logf("Error handling: Mask()")
+	for _, r := range [][3]uint{{8, 5, 4}, {8, 0, 9}, {0, 0, 0}} {
+		if _, err := Mask(r[0], r[1], r[2]); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
 	}
+}
 
-	t.Logf("Exex: Rsh()")
-	for _, s := range pattern {
-		spctr.SetString(s, 2)
-		if got := Rsh(spctr, 2); got.Bit() != "0b"+s {
-			t.Errorf("Expected 0b%v, got %v", s, got.Bit())
+func TestOneHotIndex(t *testing.T) {
+	spctr, _ := NewSpectrum(100)
+
+	t.Logf("Exec: OneHotIndex()")
+	for _, i := range []int{0, 6, 63, 64, 99} {
+		spctr.Clear()
+		spctr.SetBit(i)
+		if got, err := spctr.OneHotIndex(); err != nil {
+			t.Fatal(err)
+		} else if got != i {
+			t.Errorf("Expected %d, got %d", i, got)
 		}
 	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: OneHotIndex()")
+	spctr.Clear()
+	if _, err := spctr.OneHotIndex(); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+	spctr.SetBit(3)
+	spctr.SetBit(70)
+	if _, err := spctr.OneHotIndex(); !errors.Is(err, ErrInvalidArgument) || spctr.IsOneHot() {
+		t.Error("Error handling may not be appropriate.")
+	}
 }
 
-func TestLsh(t *testing.T) {
-	spctr, _ := NewSpectrum(8)
+func TestAdjustOnesCount(t *testing.T) {
+	spctr, _ := NewSpectrum(64)
 
-	pattern := []string{
-		"11111111",
-		"10101010",
-		"01010101",
-		"00000000",
+	t.Logf("Exec: AdjustOnesCount()")
+	spctr.AdjustOnesCount(8)
+	testOnesCount(t, spctr, 8)
+
+	spctr.AdjustOnesCount(4)
+	testOnesCount(t, spctr, 4)
+
+	for _, l := range []uint{1, 7, 64, 130} {
+		spctr, _ := NewSpectrum(l)
+		for n := uint(0); n <= l; n++ {
+			spctr.AdjustOnesCount(n)
+			testOnesCount(t, spctr, n)
+		}
+		for n := l; 0 < n; n-- {
+			spctr.AdjustOnesCount(n)
+			testOnesCount(t, spctr, n)
+		}
 	}
 
-	t.Logf("Exec: Lsh()")
-	for _, s := range pattern {
-		spctr.SetString(s, 2)
-		if got := Lsh(spctr, 2); got.Bit() != "0b"+s {
-			t.Errorf("Expected 0x%v, got %v", s, got.Bit())
+	spctr.AdjustOnesCount(65)
+	testOnesCount(t, spctr, 64)
+}
+
+func TestMutate(t *testing.T) {
+	spctr, _ := NewSpectrum(64)
+	spctr.AdjustOnesCount(32)
+
+	t.Logf("Exec: Mutate()")
+	for _, k := range []uint{0, 1, 10, 64} {
+		before := spctr.Copy()
+		if d, _ := HammingDistance(before, spctr.Mutate(k)); d != k {
+			t.Errorf("Mutate(%d) expected distance %d, got %d", k, k, d)
 		}
 	}
+
+	before := spctr.Copy()
+	if d, _ := HammingDistance(before, spctr.Mutate(100)); d != 64 {
+		t.Errorf("Mutate(100) expected distance %d, got %d", 64, d)
+	}
+
+	spctr.Seed(1)
+	want := spctr.Copy().Mutate(5)
+	spctr.Seed(1)
+	if got := spctr.Copy().Mutate(5); !got.Equal(want) {
+		t.Errorf("Expected reproducible mutation after Seed()")
+	}
 }
 
-func TestMerge(t *testing.T) {
-	x, _ := NewSpectrum(8)
-	y, _ := NewSpectrum(8)
+func TestShuffle(t *testing.T) {
+	spctr, _ := NewSpectrum(256)
+	spctr.AdjustOnesCount(100)
 
-	x.SetString("10101010", 2)
-	y.SetString("10011001", 2)
+	t.Logf("Exec: Shuffle()")
+	before := spctr.Copy()
+	spctr.Shuffle()
+	if got := spctr.OnesCount(); got != 100 || onesCount(spctr.bitVector) != 100 {
+		t.Errorf("Expected %d, got %d", 100, got)
+	}
+	if spctr.Equal(before) {
+		t.Errorf("Expected %v to be shuffled", before.Hex())
+	}
 
-	if got, _ := Merge(x, y); got.Len() != 16 || got.String(2) != "1010101010011001" {
-		t.Errorf("Expected 0x%v, got %v", "1010101010011001", got.Bit())
+	spctr.Seed(1)
+	want := spctr.Copy().Shuffle()
+	spctr.Seed(1)
+	if got := spctr.Copy().Shuffle(); !got.Equal(want) {
+		t.Errorf("Expected reproducible shuffle after Seed()")
+	}
+}
+
+func TestRandomFill(t *testing.T) {
+	spctr, _ := NewSpectrum(10000)
+
+	t.Logf("Exec: RandomFill()")
+	if got := spctr.RandomFill(0).OnesCount(); got != 0 {
+		t.Errorf("p=0 expected %d, got %d", 0, got)
+	}
+	if got := spctr.RandomFill(1).OnesCount(); got != 10000 {
+		t.Errorf("p=1 expected %d, got %d", 10000, got)
+	}
+	if got := spctr.RandomFill(0.3).Density(); got < 0.25 || 0.35 < got {
+		t.Errorf("p=0.3 expected density near 0.3, got %v", got)
+	}
+
+	spctr.Seed(1)
+	want := spctr.RandomFill(0.5).BigInt()
+	spctr.Seed(1)
+	if got := spctr.RandomFill(0.5).BigInt(); got.Cmp(want) != 0 {
+		t.Errorf("Expected reproducible fill after Seed()")
+	}
+
+	t.Logf("Exec: RandomFill() with p out of [0, 1]")
+	spctr.Seed(1)
+	if got := spctr.RandomFill(-0.5); !got.IsZero() {
+		t.Errorf("p=-0.5 expected zero, got %d ones", got.OnesCount())
+	}
+	if got := spctr.RandomFill(2); !got.IsAllOnes() {
+		t.Errorf("p=2 expected all ones, got %d ones", got.OnesCount())
+	}
+	if got := spctr.RandomFill(math.NaN()); !got.IsAllOnes() {
+		t.Errorf("p=NaN expected Spectrum to be unchanged, got %d ones", got.OnesCount())
+	}
+	if spctr.src.n != 0 {
+		t.Errorf("Expected no random draws, got %d", spctr.src.n)
+	}
+}
+
+func TestRandomFillWeights(t *testing.T) {
+	weights := []float64{0, 1, 0.1, 0.5, 0.9, 0.25, 0.75, 0}
+	spctr, _ := NewSpectrum(uint(len(weights)))
+	spctr.Seed(1)
+
+	t.Logf("Exec: RandomFillWeights()")
+	const trials = 10000
+	freq := make([]int, len(weights))
+	for i := 0; i < trials; i++ {
+		if _, err := spctr.RandomFillWeights(weights); err != nil {
+			t.Fatal(err)
+		}
+		spctr.ForEachSetBit(func(j int) bool {
+			freq[j]++
+			return true
+		})
+	}
+	for i, w := range weights {
+		if got := float64(freq[i]) / trials; math.Abs(got-w) > 0.02 {
+			t.Errorf("Bit %d expected frequency near %v, got %v", i, w, got)
+		}
+	}
+
+	spctr.Seed(1)
+	want, _ := spctr.RandomFillWeights(weights)
+	want = want.Copy()
+	spctr.Seed(1)
+	if got, _ := spctr.RandomFillWeights(weights); !got.Equal(want) {
+		t.Errorf("Expected reproducible fill after Seed()")
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: RandomFillWeights()")
+	before := spctr.Copy()
+	for _, w := range [][]float64{
+		weights[:7],
+		{0, 1, 0.1, 0.5, 0.9, 0.25, 0.75, 1.5},
+		{0, 1, 0.1, -0.5, 0.9, 0.25, 0.75, 0},
+		{0, 1, 0.1, math.NaN(), 0.9, 0.25, 0.75, 0},
+	} {
+		if _, err := spctr.RandomFillWeights(w); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
+	}
+	if !spctr.Equal(before) {
+		t.Errorf("Expected Spectrum to be unchanged, got %v", spctr.Bit())
+	}
+}
+
+func BenchmarkAdjustOnesCount(b *testing.B) {
+	spctr, _ := NewSpectrum(1024)
+
+	for i := 0; i < b.N; i++ {
+		spctr.SetUint64(0)
+		spctr.AdjustOnesCount(1000)
+	}
+}
+
+func TestGray(t *testing.T) {
+	spctr, _ := NewSpectrum(5)
+	prev, _ := NewSpectrum(5)
+
+	t.Logf("Exec: ToGray(), FromGray()")
+	for i := uint64(0); i < 1<<5; i++ {
+		spctr.SetUint64(i)
+		gray := spctr.ToGray()
+		if got := gray.FromGray(); !got.Equal(spctr) {
+			t.Errorf("Round trip expected %v, got %v", spctr.Bit(), got.Bit())
+		}
+		if 0 < i {
+			if d, _ := HammingDistance(prev, gray); d != 1 {
+				t.Errorf("Gray(%d) and Gray(%d) expected to differ by 1 bit, got %d", i-1, i, d)
+			}
+		}
+		prev = gray
+	}
+}
+
+func TestNextCombination(t *testing.T) {
+	for _, p := range []struct{ n, k, want uint }{{4, 2, 6}, {8, 3, 56}, {70, 1, 70}, {5, 5, 1}} {
+		spctr, _ := NewSpectrum(p.n)
+		spctr.SetRange(0, int(p.k))
+
+		t.Logf("Exec: NextCombination()")
+		seen := map[string]bool{}
+		prev := spctr
+		for ok := true; ok; spctr, ok = spctr.NextCombination() {
+			if seen[spctr.Bit()] {
+				t.Fatalf("Duplicate combination %v", spctr.Bit())
+			}
+			if spctr.OnesCount() != p.k {
+				t.Fatalf("Expected %d set bits, got %v", p.k, spctr.Bit())
+			}
+			if len(seen) != 0 && spctr.Cmp(prev) <= 0 {
+				t.Fatalf("Expected ascending order, got %v after %v", spctr.Bit(), prev.Bit())
+			}
+			seen[spctr.Bit()] = true
+			prev = spctr
+		}
+		if uint(len(seen)) != p.want {
+			t.Errorf("C(%d, %d) expected %d combinations, got %d", p.n, p.k, p.want, len(seen))
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	spctr, _ := NewSpectrum(70)
+	spctr.SetString("1000000000000000000000000000000000000000000000000000000000000010110110", 2)
+
+	t.Logf("Exec: Rank()")
+	for i, want := range map[int]uint{0: 0, 1: 0, 2: 1, 5: 3, 8: 5, 69: 5, 70: 6} {
+		if got, err := spctr.Rank(i); err != nil {
+			t.Fatal(err)
+		} else if got != want {
+			t.Errorf("Rank(%d) expected %d, got %d", i, want, got)
+		}
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: Rank()")
+	for _, i := range []int{-1, 71} {
+		if _, err := spctr.Rank(i); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
+	}
+}
+
+func TestOnesCountRange(t *testing.T) {
+	spctr, _ := NewSpectrum(70)
+	spctr.SetString("1000000000000000000000000000000000000000000000000000000000000010110110", 2)
+
+	t.Logf("Exec: OnesCountRange()")
+	for _, p := range []struct {
+		lo, hi int
+		want   uint
+	}{{0, 0, 0}, {0, 70, 6}, {1, 3, 2}, {3, 7, 2}, {5, 13, 2}, {7, 69, 1}, {69, 70, 1}} {
+		if got, err := spctr.OnesCountRange(p.lo, p.hi); err != nil {
+			t.Fatal(err)
+		} else if got != p.want {
+			t.Errorf("OnesCountRange(%d, %d) expected %d, got %d", p.lo, p.hi, p.want, got)
+		}
+		rhi, _ := spctr.Rank(p.hi)
+		rlo, _ := spctr.Rank(p.lo)
+		if got, _ := spctr.OnesCountRange(p.lo, p.hi); got != rhi-rlo {
+			t.Errorf("OnesCountRange(%d, %d) expected Rank difference %d, got %d", p.lo, p.hi, rhi-rlo, got)
+		}
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: OnesCountRange()")
+	for _, r := range [][2]int{{-1, 3}, {5, 4}, {0, 71}} {
+		if _, err := spctr.OnesCountRange(r[0], r[1]); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	spctr, _ := NewSpectrum(130)
+
+	pattern := []string{
+		"10110110",
+		"1000000000000000000000000000000000000000000000000000000000000010110110",
+		"1" + strings.Repeat("0", 128) + "1",
+	}
+
+	t.Logf("Exec: Select()")
+	for _, p := range pattern {
+		spctr.SetString(p, 2)
+		for k := uint(1); k <= spctr.OnesCount(); k++ {
+			i, err := spctr.Select(k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok, _ := spctr.TestBit(i); !ok {
+				t.Errorf("Select(%d) = %d is not a set bit", k, i)
+			}
+			if got, _ := spctr.Rank(i); got != k-1 {
+				t.Errorf("Rank(Select(%d)) expected %d, got %d", k, k-1, got)
+			}
+		}
+
+		// -- exception usecase --
+		t.Logf("Error handling: Select()")
+		if _, err := spctr.Select(spctr.OnesCount() + 1); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
+		if _, err := spctr.Select(0); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
+	}
+}
+
+func TestWindowOnesCount(t *testing.T) {
+	spctr, _ := NewSpectrum(10)
+	spctr.SetString("1011110001", 2)
+
+	t.Logf("Exec: WindowOnesCount()")
+	if got, err := spctr.WindowOnesCount(4); err != nil {
+		t.Fatal(err)
+	} else if want := []uint{1, 4, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got, _ := spctr.WindowOnesCount(10); !reflect.DeepEqual(got, []uint{6}) {
+		t.Errorf("Expected %v, got %v", []uint{6}, got)
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: WindowOnesCount()")
+	if _, err := spctr.WindowOnesCount(0); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestFirstLastSetBit(t *testing.T) {
+	spctr, _ := NewSpectrum(13)
+
+	pattern := []struct {
+		bits        string
+		first, last int
+	}{
+		{"0000000000000", -1, -1},
+		{"0000000100000", 5, 5},
+		{"1000000000000", 12, 12},
+		{"0000000000001", 0, 0},
+		{"1111111111111", 0, 12},
+		{"0010000001000", 3, 10},
+	}
+
+	t.Logf("Exec: FirstSetBit(), LastSetBit()")
+	for _, p := range pattern {
+		spctr.SetString(p.bits, 2)
+		if got := spctr.FirstSetBit(); got != p.first {
+			t.Errorf("Case(0b%s) FirstSetBit() expected %d, got %d", p.bits, p.first, got)
+		}
+		if got := spctr.LastSetBit(); got != p.last {
+			t.Errorf("Case(0b%s) LastSetBit() expected %d, got %d", p.bits, p.last, got)
+		}
+	}
+}
+
+func TestNextSetBit(t *testing.T) {
+	spctr, _ := NewSpectrum(140)
+	spctr.SetPositions(3, 64, 65, 139)
+
+	t.Logf("Exec: NextSetBit()")
+	for from, want := range map[int]int{-5: 3, 0: 3, 3: 3, 4: 64, 64: 64, 65: 65, 66: 139, 139: 139, 140: -1, 200: -1} {
+		if got := spctr.NextSetBit(from); got != want {
+			t.Errorf("NextSetBit(%d) expected %d, got %d", from, want, got)
+		}
+	}
+
+	spctr.Clear()
+	if got := spctr.NextSetBit(0); got != -1 {
+		t.Errorf("Zero expected %d, got %d", -1, got)
+	}
+}
+
+func TestForEachSetBit(t *testing.T) {
+	spctr, _ := NewSpectrum(100)
+	spctr.SetString("1"+strings.Repeat("0", 90)+"101001", 2)
+
+	t.Logf("Exec: ForEachSetBit()")
+	var got []int
+	spctr.ForEachSetBit(func(i int) bool {
+		got = append(got, i)
+		return true
+	})
+	if want := []int{0, 3, 5, 96}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	got = got[:0]
+	spctr.ForEachSetBit(func(i int) bool {
+		got = append(got, i)
+		return len(got) < 2
+	})
+	if want := []int{0, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Early stop expected %v, got %v", want, got)
+	}
+}
+
+func TestForEachClearBit(t *testing.T) {
+	spctr, _ := NewSpectrum(70)
+	spctr.Fill()
+	spctr.ClearBit(1)
+	spctr.ClearBit(64)
+	spctr.ClearBit(69)
+
+	t.Logf("Exec: ForEachClearBit()")
+	var got []int
+	spctr.ForEachClearBit(func(i int) bool {
+		got = append(got, i)
+		return true
+	})
+	if want := []int{1, 64, 69}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	got = got[:0]
+	spctr.ForEachClearBit(func(i int) bool {
+		got = append(got, i)
+		return false
+	})
+	if want := []int{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Early stop expected %v, got %v", want, got)
+	}
+
+	spctr.Clear()
+	n := 0
+	spctr.ForEachClearBit(func(i int) bool {
+		n++
+		return true
+	})
+	if n != 70 {
+		t.Errorf("Zero expected %d positions, got %d", 70, n)
+	}
+}
+
+func TestFold(t *testing.T) {
+	spctr, _ := NewSpectrum(10)
+	spctr.SetString("1000101100", 2)
+
+	t.Logf("Exec: Fold()")
+	if got := spctr.Fold(0, func(acc, bit, i int) int { return acc + bit*i }); got != 2+3+5+9 {
+		t.Errorf("Weighted sum expected %d, got %d", 2+3+5+9, got)
+	}
+	if got := spctr.Fold(0, func(acc, bit, _ int) int { return acc + bit }); got != int(spctr.OnesCount()) {
+		t.Errorf("Sum expected %d, got %d", spctr.OnesCount(), got)
+	}
+
+	var order []int
+	spctr.Fold(-1, func(acc, _, i int) int {
+		if i != acc+1 {
+			t.Errorf("Index %d expected after %d", i, acc)
+		}
+		order = append(order, i)
+		return i
+	})
+	if len(order) != spctr.Len() {
+		t.Errorf("Expected %d calls, got %d", spctr.Len(), len(order))
+	}
+}
+
+func TestPositions(t *testing.T) {
+	spctr, _ := NewSpectrum(80)
+
+	t.Logf("Exec: Positions()")
+	if got := spctr.Positions(); got == nil || len(got) != 0 {
+		t.Errorf("Zero expected empty slice, got %#v", got)
+	}
+
+	t.Logf("Exec: SetPositions()")
+	spctr.SetUint64(bits32)
+	if err := spctr.SetPositions(79, 2, 64); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := spctr.Positions(), []int{2, 64, 79}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: SetPositions()")
+	if err := spctr.SetPositions(1, 80); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	} else if got, want := spctr.Positions(), []int{2, 64, 79}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected unchanged %v, got %v", want, got)
+	}
+}
+
+func TestNewSpectrumFromPositions(t *testing.T) {
+	t.Logf("Exec: NewSpectrumFromPositions()")
+	want := []int{0, 7, 63, 64, 99}
+	spctr, err := NewSpectrumFromPositions(100, []int{64, 0, 99, 7, 63})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := spctr.Positions(); spctr.Len() != 100 || !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got, _ := NewSpectrumFromPositions(100, spctr.Positions()); !got.Equal(spctr) {
+		t.Errorf("Expected %v, got %v", spctr.Hex(), got.Hex())
+	}
+	if got, _ := NewSpectrumFromPositions(8, nil); !got.IsZero() || got.Len() != 8 {
+		t.Errorf("Expected %v, got %v", "0b00000000", got.Bit())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: NewSpectrumFromPositions()")
+	if _, err := NewSpectrumFromPositions(8, []int{1, 8}); !errors.Is(err, ErrOutOfRange) {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if _, err := NewSpectrumFromPositions(8, []int{-1}); !errors.Is(err, ErrOutOfRange) {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if _, err := NewSpectrumFromPositions(8, []int{3, 5, 3}); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if _, err := NewSpectrumFromPositions(0, nil); !errors.Is(err, ErrInvalidLength) {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestSwapRange(t *testing.T) {
+	spctr, _ := NewSpectrum(12)
+	spctr.SetString("111100001010", 2)
+
+	t.Logf("Exec: SwapRange()")
+	if err := spctr.SwapRange(0, 8, 4); err != nil {
+		t.Fatal(err)
+	} else if got := spctr.Bit(); got != "0b101000001111" {
+		t.Errorf("Expected 0b101000001111, got %v", got)
+	}
+	if err := spctr.SwapRange(3, 3, 5); err != nil {
+		t.Fatal(err)
+	} else if got := spctr.Bit(); got != "0b101000001111" {
+		t.Errorf("Same range expected no-op, got %v", got)
+	}
+	if err := spctr.SwapRange(0, 3, 0); err != nil {
+		t.Fatal(err)
+	} else if got := spctr.Bit(); got != "0b101000001111" {
+		t.Errorf("Zero width expected no-op, got %v", got)
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: SwapRange()")
+	for _, r := range [][3]int{{0, 2, 4}, {0, 9, 4}, {-1, 4, 2}} {
+		if err := spctr.SwapRange(r[0], r[1], r[2]); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
+	}
+}
+
+func TestBoolSlice(t *testing.T) {
+	spctr, _ := NewSpectrum(5)
+	spctr.SetString("10011", 2)
+
+	t.Logf("Exec: ToBoolSlice()")
+	b := spctr.ToBoolSlice()
+	if want := []bool{true, true, false, false, true}; !reflect.DeepEqual(b, want) {
+		t.Errorf("Expected %v, got %v", want, b)
+	}
+
+	t.Logf("Exec: FromBoolSlice()")
+	if got, err := FromBoolSlice(b); err != nil {
+		t.Fatal(err)
+	} else if !got.Equal(spctr) {
+		t.Errorf("Expected %v, got %v", spctr.Bit(), got.Bit())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: FromBoolSlice()")
+	if _, err := FromBoolSlice(nil); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestSetRange(t *testing.T) {
+	spctr, _ := NewSpectrum(20)
+
+	t.Logf("Exec: SetRange()")
+	if err := spctr.SetRange(6, 10); err != nil {
+		t.Fatal(err)
+	} else if got := spctr.Bit(); got != "0b00000000001111000000" {
+		t.Errorf("Expected 0b00000000001111000000, got %v", got)
+	}
+	if err := spctr.SetRange(0, 20); err != nil {
+		t.Fatal(err)
+	} else if !spctr.IsAllOnes() {
+		t.Errorf("Expected all ones, got %v", spctr.Bit())
+	}
+
+	t.Logf("Exec: ClearRange()")
+	if err := spctr.ClearRange(7, 17); err != nil {
+		t.Fatal(err)
+	} else if got := spctr.Bit(); got != "0b11100000000001111111" {
+		t.Errorf("Expected 0b11100000000001111111, got %v", got)
+	}
+	if err := spctr.ClearRange(0, 20); err != nil {
+		t.Fatal(err)
+	} else if !spctr.IsZero() {
+		t.Errorf("Expected zero, got %v", spctr.Bit())
+	}
+	if err := spctr.SetRange(5, 5); err != nil {
+		t.Fatal(err)
+	} else if !spctr.IsZero() {
+		t.Errorf("Empty range expected zero, got %v", spctr.Bit())
+	}
+
+	t.Logf("Exec: ToggleRange()")
+	spctr.SetString("10110011100011110000", 2)
+	if err := spctr.ToggleRange(2, 9); err != nil {
+		t.Fatal(err)
+	} else if got := spctr.Bit(); got != "0b10110011100100001100" {
+		t.Errorf("Expected 0b10110011100100001100, got %v", got)
+	}
+	want := spctr.Not()
+	if err := spctr.ToggleRange(0, 20); err != nil {
+		t.Fatal(err)
+	} else if !spctr.Equal(want) {
+		t.Errorf("Expected %v, got %v", want.Bit(), spctr.Bit())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: SetRange(), ClearRange(), ToggleRange()")
+	for _, r := range [][2]int{{-1, 3}, {4, 3}, {0, 21}} {
+		if err := spctr.SetRange(r[0], r[1]); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
+		if err := spctr.ClearRange(r[0], r[1]); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
+		if err := spctr.ToggleRange(r[0], r[1]); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
+	}
+}
+
+func TestField(t *testing.T) {
+	spctr, _ := NewSpectrum(16)
+	spctr.SetString("1010110011110001", 2)
+
+	t.Logf("Exec: ExtractField()")
+	if got, err := spctr.ExtractField(4, 8); err != nil {
+		t.Fatal(err)
+	} else if got.Bit() != "0b11001111" {
+		t.Errorf("Expected 0b11001111, got %v", got.Bit())
+	}
+
+	t.Logf("Exec: InsertField()")
+	field, _ := NewSpectrum(6)
+	field.SetString("010101", 2)
+	if err := spctr.InsertField(5, field); err != nil {
+		t.Fatal(err)
+	} else if got := spctr.Bit(); got != "0b1010101010110001" {
+		t.Errorf("Expected 0b1010101010110001, got %v", got)
+	}
+	if got, _ := spctr.ExtractField(5, 6); !got.Equal(field) {
+		t.Errorf("Expected %v, got %v", field.Bit(), got.Bit())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: ExtractField(), InsertField()")
+	if _, err := spctr.ExtractField(10, 7); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if _, err := spctr.ExtractField(3, 0); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if err := spctr.InsertField(11, field); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestChunks(t *testing.T) {
+	spctr, _ := NewSpectrum(16)
+	spctr.SetString("1010110011110001", 2)
+
+	t.Logf("Exec: Chunks()")
+	for _, p := range []struct {
+		width int
+		want  []uint64
+	}{
+		{4, []uint64{0x1, 0xF, 0xC, 0xA}},
+		{5, []uint64{0x11, 0x07, 0x0B, 0x1}},
+		{16, []uint64{0xACF1}},
+		{64, []uint64{0xACF1}},
+	} {
+		if got, err := spctr.Chunks(p.width); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(got, p.want) {
+			t.Errorf("Chunks(%d) expected %x, got %x", p.width, p.want, got)
+		}
+	}
+
+	wide, _ := NewSpectrum(130)
+	wide.Fill()
+	if got, _ := wide.Chunks(64); !reflect.DeepEqual(got, []uint64{bits64, bits64, 0x3}) {
+		t.Errorf("Expected %x, got %x", []uint64{bits64, bits64, 0x3}, got)
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: Chunks()")
+	for _, width := range []int{0, -1, 65} {
+		if _, err := spctr.Chunks(width); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
+	}
+}
+
+// --- rand ---
+
+func TestSeed(t *testing.T) {
+	var cp []int
+	spctr, _ := NewSpectrum(64)
+
+	t.Logf("Exec: Seed()")
+	spctr.Seed(1)
+	for i := 0; i < 5; i++ {
+		cp = append(cp, spctr.rnd.Intn(10))
+	}
+
+	spctr.Seed(1)
+	for i := 0; i < 5; i++ {
+		if got := spctr.rnd.Intn(10); got != cp[i] {
+			t.Errorf("rand value expected %d, got %d", cp[i], got)
+		}
+	}
+}
+
+func TestCloneRand(t *testing.T) {
+	spctr, _ := NewSpectrum(128)
+	spctr.Seed(3)
+	spctr.AdjustOnesCount(40)
+	spctr.Shuffle()
+
+	t.Logf("Exec: CloneRand()")
+	clone := spctr.CloneRand()
+	if !clone.Equal(spctr) || clone.bitVector == spctr.bitVector {
+		t.Fatalf("Expected independent copy of %v, got %v", spctr.Hex(), clone.Hex())
+	}
+	for i := 0; i < 5; i++ {
+		want := spctr.AdjustOnesCount(uint(10 * i)).Mutate(7)
+		if got := clone.AdjustOnesCount(uint(10 * i)).Mutate(7); !got.Equal(want) {
+			t.Errorf("Step %d expected %v, got %v", i, want.Hex(), got.Hex())
+		}
+	}
+	if got, want := clone.rnd.Int63(), spctr.rnd.Int63(); got != want {
+		t.Errorf("rand value expected %d, got %d", want, got)
+	}
+
+	t.Logf("Exec: CloneRand() after decode")
+	var decoded Spectrum
+	data, _ := spctr.MarshalBinary()
+	decoded.UnmarshalBinary(data)
+	decoded.Seed(5)
+	decoded.Mutate(3)
+	if got, want := decoded.CloneRand().Mutate(3), decoded.Mutate(3); !got.Equal(want) {
+		t.Errorf("Expected %v, got %v", want.Hex(), got.Hex())
+	}
+}
+
+func TestCopyReproducible(t *testing.T) {
+	spctr, _ := NewSpectrum(64)
+
+	t.Logf("Exec: Copy() after Seed()")
+	var want []uint64
+	spctr.Seed(1)
+	for i := 0; i < 5; i++ {
+		want = append(want, spctr.Uint64n(8))
+	}
+
+	spctr.Seed(1)
+	for i := 0; i < 5; i++ {
+		if got := spctr.Uint64n(8); got != want[i] {
+			t.Errorf("Uint64n() expected %x, got %x", want[i], got)
+		}
+	}
+
+	t.Logf("Exec: consecutive Copy()")
+	spctr.SetUint64(bits32)
+	mutate := func() (x, y *Spectrum) {
+		return spctr.Copy().Mutate(5), spctr.Copy().Mutate(5)
+	}
+	spctr.Seed(3)
+	x1, y1 := mutate()
+	if x1.Equal(y1) {
+		t.Errorf("Expected consecutive copies to differ, got %v", x1.Hex())
+	}
+	spctr.Seed(3)
+	if x2, y2 := mutate(); !x2.Equal(x1) || !y2.Equal(y1) {
+		t.Errorf("Expected (%v, %v), got (%v, %v)", x1.Hex(), y1.Hex(), x2.Hex(), y2.Hex())
+	}
+
+	t.Logf("Exec: CopyWithSeed()")
+	x := spctr.CopyWithSeed(2).AdjustOnesCount(32)
+	y := spctr.CopyWithSeed(2).AdjustOnesCount(32)
+	if !x.Equal(y) {
+		t.Errorf("Expected %v, got %v", x.Bit(), y.Bit())
+	}
+}
+
+func TestReadOnlyKeepsRand(t *testing.T) {
+	a, _ := NewSpectrum(64)
+	b, _ := NewSpectrum(64)
+	a.SetUint64(0xF0F0A5A5)
+	b.SetUint64(0xF0F0A5A5)
+	a.Seed(7)
+	b.Seed(7)
+
+	t.Logf("Exec: read-only operations after Seed()")
+	identity := make([]int, 64)
+	for i := range identity {
+		identity[i] = i
+	}
+	a.Copy()
+	a.Not()
+	a.Reverse()
+	a.ToGray()
+	a.FromGray()
+	Rsh(a, 3)
+	Lsh(a, 3)
+	Rotate(a, -5)
+	ShiftLeft(a, 2)
+	ShiftRight(a, 2)
+	a.Permute(identity)
+	OrAll(a, b)
+	AndAll(a, b)
+	Majority(a, b, a)
+	Max(a, b)
+	Min(a, b)
+	Crossover(a, b, 10)
+	a.LFSRNext([]int{0, 1})
+	a.CAStep(90)
+	a.Autocorrelation(3)
+	a.Period()
+	a.SwapBytes()
+	a.SwapNibbles()
+	a.NextCombination()
+	if got, want := a.AdjustOnesCount(20), b.AdjustOnesCount(20); !got.Equal(want) {
+		t.Errorf("Expected %v, got %v", want.Hex(), got.Hex())
+	}
+
+	t.Logf("Exec: concurrent read-only operations")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				a.Not()
+				Rotate(a, j)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// --- bitwise operation ---
+
+func TestAnd(t *testing.T) {
+	spctr32, _ := NewSpectrum(64)
+	spctr64, _ := NewSpectrum(64)
+
+	spctr32.SetUint64(bits32)
+	spctr64.SetUint64(bits64)
+
+	t.Logf("Exec: And()")
+	if got := And(spctr64, spctr32); got.Cmp(big.NewInt(bits32)) != 0 {
+		t.Errorf("Expected %x, got %x", bits32, got)
+	}
+}
+
+func TestOr(t *testing.T) {
+	spctr32, _ := NewSpectrum(64)
+	spctr64, _ := NewSpectrum(64)
+
+	spctr32.SetUint64(bits32)
+	spctr64.SetUint64(bits64)
+
+	t.Logf("Exec: Or()")
+	if got := Or(spctr64, spctr32); got.Cmp(big.NewInt(0).SetUint64(bits64)) != 0 {
+		t.Errorf("Expected %x, got %x", bits64, got)
+	}
+}
+
+func TestAndNot(t *testing.T) {
+	spctr32, _ := NewSpectrum(64)
+	spctr64, _ := NewSpectrum(64)
+
+	spctr32.SetUint64(bits32)
+	spctr64.SetUint64(bits64 - 1)
+
+	t.Logf("Exec: AndNot()")
+	want, _ := big.NewInt(0).SetString("00000001", 16)
+	if got := AndNot(spctr32, spctr64); got.Cmp(want) != 0 {
+		t.Errorf("Expected %x, got %x", want, got)
+	}
+
+	want, _ = big.NewInt(0).SetString("ffffffff00000000", 16)
+	if got := AndNot(spctr64, spctr32); got.Cmp(want) != 0 {
+		t.Errorf("Expected %x, got %x", want, got)
+	}
+
+}
+
+func TestXor(t *testing.T) {
+	spctr32, _ := NewSpectrum(64)
+	spctr64, _ := NewSpectrum(64)
+
+	spctr32.SetUint64(bits32)
+	spctr64.SetUint64(bits64)
+
+	t.Logf("Exec: Xor()")
+	want, _ := big.NewInt(0).SetString("ffffffff00000000", 16)
+	if got := Xor(spctr64, spctr32); got.Cmp(want) != 0 {
+		t.Errorf("Expected %x, got %x", want, got)
+	}
+}
+
+// --- shift operation ---
+
+func TestRsh(t *testing.T) {
+	spctr, _ := NewSpectrum(8)
+
+	pattern := []string{
+		"11111111",
+		"10101010",
+		"01010101",
+		"00000000",
+	}
+
+	t.Logf("Exex: Rsh()")
+	for _, s := range pattern {
+		spctr.SetString(s, 2)
+		if got := Rsh(spctr, 2); got.Bit() != "0b"+s {
+			t.Errorf("Expected 0b%v, got %v", s, got.Bit())
+		}
+	}
+}
+
+func TestLsh(t *testing.T) {
+	spctr, _ := NewSpectrum(8)
+
+	pattern := []string{
+		"11111111",
+		"10101010",
+		"01010101",
+		"00000000",
+	}
+
+	t.Logf("Exec: Lsh()")
+	for _, s := range pattern {
+		spctr.SetString(s, 2)
+		if got := Lsh(spctr, 2); got.Bit() != "0b"+s {
+			t.Errorf("Expected 0x%v, got %v", s, got.Bit())
+		}
+	}
+}
+
+func TestShiftModulo(t *testing.T) {
+	spctr, _ := NewSpectrum(8)
+	spctr.SetString("10110001", 2)
+
+	t.Logf("Exec: Rsh(), Lsh() with n >= length")
+	for _, n := range []uint{8, 11, 8*1000 + 3} {
+		if got, want := Rsh(spctr, n), Rsh(spctr, n%8); !got.Equal(want) {
+			t.Errorf("Rsh(%d) expected %v, got %v", n, want.Bit(), got.Bit())
+		}
+		if got, want := Lsh(spctr, n), Lsh(spctr, n%8); !got.Equal(want) {
+			t.Errorf("Lsh(%d) expected %v, got %v", n, want.Bit(), got.Bit())
+		}
+	}
+
+	if got := Rsh(spctr, 8); !got.Equal(spctr) {
+		t.Errorf("Rsh(length) expected %v, got %v", spctr.Bit(), got.Bit())
+	}
+	if got := Lsh(spctr, 11); got.Bit() != "0b10001101" {
+		t.Errorf("Lsh(length+3) expected 0b10001101, got %v", got.Bit())
+	}
+	if got := Rsh(spctr, 11); got.Bit() != "0b00110110" {
+		t.Errorf("Rsh(length+3) expected 0b00110110, got %v", got.Bit())
+	}
+}
+
+func TestRotate(t *testing.T) {
+	spctr, _ := NewSpectrum(8)
+	spctr.SetString("10110001", 2)
+
+	t.Logf("Exec: Rotate()")
+	for _, n := range []int{1, 3, 11, -1, -3, -11} {
+		want := Lsh(spctr, uint(n))
+		if n < 0 {
+			want = Rsh(spctr, uint(-n))
+		}
+		if got := Rotate(spctr, n); !got.Equal(want) {
+			t.Errorf("Rotate(%d) expected %v, got %v", n, want.Bit(), got.Bit())
+		}
+	}
+
+	got := Rotate(spctr, 0)
+	if !got.Equal(spctr) {
+		t.Errorf("Rotate(0) expected %v, got %v", spctr.Bit(), got.Bit())
+	} else if got.bitVector == spctr.bitVector {
+		t.Errorf("Expected call by value, but got call by reference.")
+	}
+}
+
+func TestLogicalShift(t *testing.T) {
+	spctr, _ := NewSpectrum(8)
+	spctr.SetString("10110001", 2)
+
+	pattern := []struct {
+		n     uint
+		left  string
+		right string
+	}{
+		{0, "10110001", "10110001"},
+		{3, "10001000", "00010110"},
+		{8, "00000000", "00000000"},
+		{100, "00000000", "00000000"},
+		{1 << 40, "00000000", "00000000"},
+	}
+
+	t.Logf("Exec: ShiftLeft(), ShiftRight()")
+	for _, p := range pattern {
+		if got := ShiftLeft(spctr, p.n); got.Bit() != "0b"+p.left {
+			t.Errorf("ShiftLeft(%d) expected 0b%v, got %v", p.n, p.left, got.Bit())
+		}
+		if got := ShiftRight(spctr, p.n); got.Bit() != "0b"+p.right {
+			t.Errorf("ShiftRight(%d) expected 0b%v, got %v", p.n, p.right, got.Bit())
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	x, _ := NewSpectrum(8)
+	y, _ := NewSpectrum(8)
+
+	x.SetString("10101010", 2)
+	y.SetString("10011001", 2)
+
+	if got, _ := Merge(x, y); got.Len() != 16 || got.String(2) != "1010101010011001" {
+		t.Errorf("Expected 0x%v, got %v", "1010101010011001", got.Bit())
+	}
+}
+
+func TestNot(t *testing.T) {
+	t.Logf("Exec: Not()")
+	for _, l := range []uint{7, 13, 64} {
+		spctr, _ := NewSpectrum(l)
+		if got := spctr.Not(); got.OnesCount() != l || got.Len() != int(l) {
+			t.Errorf("%dbits Not() of zero expected all ones, got %v", l, got.Bit())
+		}
+
+		spctr.SetUint64(0x55)
+		not := spctr.Not()
+		if got := Or(spctr, not); got.Cmp(mask(int(l))) != 0 {
+			t.Errorf("%dbits Or(s, Not()) expected all ones, got %x", l, got)
+		}
+		if got := And(spctr, not); got.Sign() != 0 {
+			t.Errorf("%dbits And(s, Not()) expected zero, got %x", l, got)
+		}
+	}
+}
+
+func TestSetBit(t *testing.T) {
+	spctr, _ := NewSpectrum(8)
+
+	t.Logf("Exec: SetBit()")
+	if err := spctr.SetBit(7); err != nil {
+		t.Fatal(err)
+	} else if got := spctr.Bit(); got != "0b10000000" {
+		t.Errorf("Expected 0b10000000, got %v", got)
+	}
+
+	t.Logf("Exec: ClearBit()")
+	if err := spctr.ClearBit(7); err != nil {
+		t.Fatal(err)
+	} else if got := spctr.Bit(); got != "0b00000000" {
+		t.Errorf("Expected 0b00000000, got %v", got)
+	}
+
+	t.Logf("Exec: ToggleBit()")
+	spctr.SetString("10101010", 2)
+	spctr.ToggleBit(3)
+	spctr.ToggleBit(3)
+	if got := spctr.Bit(); got != "0b10101010" {
+		t.Errorf("Expected 0b10101010, got %v", got)
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: SetBit(), ClearBit(), ToggleBit()")
+	for _, i := range []int{-1, 8} {
+		if err := spctr.SetBit(i); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
+		if err := spctr.ClearBit(i); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
+		if err := spctr.ToggleBit(i); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
+	}
+}
+
+func TestTestBit(t *testing.T) {
+	spctr, _ := NewSpectrum(8)
+	spctr.SetString("10000001", 2)
+
+	t.Logf("Exec: TestBit()")
+	for i := 0; i < 8; i++ {
+		want := i == 0 || i == 7
+		if got, err := spctr.TestBit(i); err != nil {
+			t.Fatal(err)
+		} else if got != want {
+			t.Errorf("Bit %d expected %v, got %v", i, want, got)
+		}
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: TestBit()")
+	if _, err := spctr.TestBit(8); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestBitwiseSpectrum(t *testing.T) {
+	x, _ := NewSpectrum(4)
+	y, _ := NewSpectrum(8)
+
+	x.SetString("1010", 2)
+	y.SetString("11000110", 2)
+
+	pattern := []struct {
+		name string
+		fn   func(a, b *Spectrum) (*Spectrum, error)
+		want string
+	}{
+		{"AndS", AndS, "0b00000010"},
+		{"OrS", OrS, "0b11001110"},
+		{"AndNotS", AndNotS, "0b00001000"},
+		{"XorS", XorS, "0b11001100"},
+	}
+
+	for _, p := range pattern {
+		t.Logf("Exec: %s()", p.name)
+		if got, err := p.fn(x, y); err != nil {
+			t.Fatal(err)
+		} else if got.Len() != 8 || got.Bit() != p.want {
+			t.Errorf("%s() expected %v, got %v", p.name, p.want, got.Bit())
+		}
+	}
+
+	t.Logf("Exec: bitwise operation with 32-bit and 64-bit operands")
+	a, _ := NewSpectrum(32)
+	b, _ := NewSpectrum(64)
+	a.SetUint64(bits32)
+	b.SetUint64(bits64)
+	if got := And(a, b); got.BitLen() > 32 || got.Uint64() != bits32 {
+		t.Errorf("And() expected %x, got %x", bits32, got)
+	}
+	if got := Or(a, b); got.BitLen() > 64 || got.Uint64() != bits64 {
+		t.Errorf("Or() expected %x, got %x", bits64, got)
+	}
+	if got := AndNot(b, a); got.BitLen() > 64 || got.Uint64() != bits64^bits32 {
+		t.Errorf("AndNot() expected %x, got %x", bits64^bits32, got)
+	}
+	if got, _ := XorS(a, b); got.Len() != 64 || got.Uint64() != bits64^bits32 {
+		t.Errorf("XorS() expected %x, got %v", bits64^bits32, got.Hex())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: Difference(), SymmetricDifference()")
+	if _, err := Difference(a, b); !errors.Is(err, ErrLengthMismatch) {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if _, err := SymmetricDifference(a, b); !errors.Is(err, ErrLengthMismatch) {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestCmp(t *testing.T) {
+	var specs []*Spectrum
+	for _, p := range []struct {
+		length uint
+		x      uint64
+	}{{8, 5}, {4, 5}, {16, 1}, {8, 0xFF}, {4, 0}, {8, 1}} {
+		s, _ := NewSpectrum(p.length)
+		s.SetUint64(p.x)
+		specs = append(specs, s)
+	}
+
+	t.Logf("Exec: Cmp()")
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Cmp(specs[j]) < 0 })
+
+	want := []string{"0b0000", "0b00000001", "0b0000000000000001", "0b0101", "0b00000101", "0b11111111"}
+	for i, s := range specs {
+		if s.Bit() != want[i] {
+			t.Errorf("Index %d expected %s, got %s", i, want[i], s.Bit())
+		}
+	}
+	if got := specs[0].Cmp(specs[0].Copy()); got != 0 {
+		t.Errorf("Expected %d, got %d", 0, got)
+	}
+}
+
+func TestHash(t *testing.T) {
+	x, _ := NewSpectrum(8)
+	y, _ := NewSpectrum(4)
+	x.SetUint64(5)
+	y.SetUint64(5)
+
+	t.Logf("Exec: Hash()")
+	if x.Hash() != x.Copy().Hash() {
+		t.Errorf("Expected equal Spectrums to hash identically")
+	}
+	if x.Hash() == y.Hash() {
+		t.Errorf("Expected Spectrums of different length to hash differently")
+	}
+
+	m := map[uint64]*Spectrum{}
+	for i := uint64(0); i < 256; i++ {
+		x.SetUint64(i)
+		m[x.Hash()] = x.Copy()
+	}
+	if len(m) != 256 {
+		t.Errorf("Expected %d distinct hashes, got %d", 256, len(m))
+	}
+}
+
+func TestLeadingTrailingZeros(t *testing.T) {
+	spctr, _ := NewSpectrum(64)
+
+	t.Logf("Exec: LeadingZeros(), TrailingZeros()")
+	if lz, tz := spctr.LeadingZeros(), spctr.TrailingZeros(); lz != 64 || tz != 64 {
+		t.Errorf("Zero expected (64, 64), got (%d, %d)", lz, tz)
+	}
+
+	spctr.SetUint64(1)
+	if lz, tz := spctr.LeadingZeros(), spctr.TrailingZeros(); lz != 63 || tz != 0 {
+		t.Errorf("Case(0x1) expected (63, 0), got (%d, %d)", lz, tz)
+	}
+
+	spctr.SetUint64(0x00F0)
+	if lz, tz := spctr.LeadingZeros(), spctr.TrailingZeros(); lz != 56 || tz != 4 {
+		t.Errorf("Case(0xf0) expected (56, 4), got (%d, %d)", lz, tz)
+	}
+}
+
+func TestReverse(t *testing.T) {
+	pattern := []struct {
+		length uint
+		in     string
+		want   string
+	}{
+		{4, "0001", "1000"},
+		{7, "1100100", "0010011"},
+		{13, "0000000000111", "1110000000000"},
+		{13, "1010000000001", "1000000000101"},
+	}
+
+	t.Logf("Exec: Reverse()")
+	for _, p := range pattern {
+		spctr, _ := NewSpectrum(p.length)
+		spctr.SetString(p.in, 2)
+		if got := spctr.Reverse(); got.Len() != int(p.length) || got.Bit() != "0b"+p.want {
+			t.Errorf("Expected 0b%v, got %v", p.want, got.Bit())
+		}
+	}
+}
+
+func TestPermute(t *testing.T) {
+	spctr, _ := NewSpectrum(8)
+	spctr.SetString("10110001", 2)
+
+	t.Logf("Exec: Permute()")
+	identity := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	if got, _ := spctr.Permute(identity); !got.Equal(spctr) {
+		t.Errorf("Expected %v, got %v", spctr.Bit(), got.Bit())
+	}
+	reverse := []int{7, 6, 5, 4, 3, 2, 1, 0}
+	if got, _ := spctr.Permute(reverse); !got.Equal(spctr.Reverse()) {
+		t.Errorf("Expected %v, got %v", spctr.Reverse().Bit(), got.Bit())
+	}
+	if got, _ := spctr.Permute([]int{1, 0, 3, 2, 5, 4, 7, 6}); got.Bit() != "0b01110010" {
+		t.Errorf("Expected %v, got %v", "0b01110010", got.Bit())
+	}
+	if got, _ := spctr.Permute([]int{0, 0, 0, 0, 7, 7, 7, 7}); got.Bit() != "0b11111111" || got.OnesCount() != 8 {
+		t.Errorf("Expected %v, got %v", "0b11111111", got.Bit())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: Permute()")
+	if _, err := spctr.Permute(identity[:7]); !errors.Is(err, ErrLengthMismatch) {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if _, err := spctr.Permute([]int{0, 1, 2, 3, 4, 5, 6, 8}); !errors.Is(err, ErrOutOfRange) {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestSwapBytes(t *testing.T) {
+	spctr, _ := NewSpectrum(32)
+	spctr.SetUint64(0x12345678)
+
+	t.Logf("Exec: SwapBytes()")
+	if got, err := spctr.SwapBytes(); err != nil {
+		t.Fatal(err)
+	} else if got.Len() != 32 || got.Uint64() != 0x78563412 {
+		t.Errorf("Expected %x, got %v", 0x78563412, got.Hex())
+	}
+	swapped, _ := spctr.SwapBytes()
+	if got, _ := swapped.SwapBytes(); !got.Equal(spctr) {
+		t.Errorf("Expected %v, got %v", spctr.Hex(), got.Hex())
+	}
+
+	t.Logf("Exec: SwapNibbles()")
+	if got, err := spctr.SwapNibbles(); err != nil {
+		t.Fatal(err)
+	} else if got.Len() != 32 || got.Uint64() != 0x21436587 {
+		t.Errorf("Expected %x, got %v", 0x21436587, got.Hex())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: SwapBytes(), SwapNibbles()")
+	odd, _ := NewSpectrum(12)
+	if _, err := odd.SwapBytes(); !errors.Is(err, ErrInvalidLength) {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if _, err := odd.SwapNibbles(); !errors.Is(err, ErrInvalidLength) {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	x, _ := NewSpectrum(4)
+	y, _ := NewSpectrum(8)
+
+	x.SetString("0101", 2)
+	y.SetString("00000101", 2)
+
+	t.Logf("Exec: Equal()")
+	if x.Equal(y) {
+		t.Errorf("Expected %v and %v to differ in length", x.Bit(), y.Bit())
+	}
+	if !x.Equal(x.Copy()) {
+		t.Errorf("Expected %v to equal its copy", x.Bit())
+	}
+
+	t.Logf("Exec: EqualValue()")
+	if !x.EqualValue(y) {
+		t.Errorf("Expected %v and %v to be numerically equal", x.Bit(), y.Bit())
+	}
+	if y.SetString("00000110", 2); x.EqualValue(y) {
+		t.Errorf("Expected %v and %v to differ in value", x.Bit(), y.Bit())
+	}
+}
+
+func TestSetRelationship(t *testing.T) {
+	x, _ := NewSpectrum(8)
+	y, _ := NewSpectrum(8)
+	z, _ := NewSpectrum(4)
+
+	x.SetString("11001010", 2)
+	y.SetString("01001000", 2)
+	z.SetString("0101", 2)
+
+	t.Logf("Exec: Contains(), IsSubset()")
+	if !x.Contains(y) || y.Contains(x) {
+		t.Errorf("Expected %v to contain %v", x.Bit(), y.Bit())
+	}
+	if !y.IsSubset(x) || x.IsSubset(y) {
+		t.Errorf("Expected %v to be a subset of %v", y.Bit(), x.Bit())
+	}
+
+	t.Logf("Exec: Intersects(), IsDisjoint()")
+	if !x.Intersects(y) || x.IsDisjoint(y) {
+		t.Errorf("Expected %v and %v to intersect", x.Bit(), y.Bit())
+	}
+	if x.Intersects(z) || !x.IsDisjoint(z) {
+		t.Errorf("Expected %v and %v to be disjoint", x.Bit(), z.Bit())
+	}
+}
+
+func TestInterleave(t *testing.T) {
+	x, _ := NewSpectrum(4)
+	y, _ := NewSpectrum(4)
+
+	x.SetString("1100", 2)
+	y.SetString("1010", 2)
+
+	t.Logf("Exec: Interleave()")
+	s, err := Interleave(x, y)
+	if err != nil {
+		t.Fatal(err)
+	} else if s.Bit() != "0b11011000" {
+		t.Errorf("Expected 0b11011000, got %v", s.Bit())
+	}
+
+	t.Logf("Exec: Deinterleave()")
+	if gx, gy, err := Deinterleave(s); err != nil {
+		t.Fatal(err)
+	} else if !gx.Equal(x) || !gy.Equal(y) {
+		t.Errorf("Expected (%v, %v), got (%v, %v)", x.Bit(), y.Bit(), gx.Bit(), gy.Bit())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: Interleave(), Deinterleave()")
+	z, _ := NewSpectrum(5)
+	if _, err := Interleave(x, z); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if _, _, err := Deinterleave(z); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestStride(t *testing.T) {
+	spctr, _ := NewSpectrum(10)
+	spctr.SetString("1011001110", 2)
+
+	t.Logf("Exec: Stride()")
+	for _, p := range []struct {
+		offset, step int
+		want         string
+	}{
+		{0, 1, "0b1011001110"},
+		{1, 3, "0b101"},
+		{0, 3, "0b1110"},
+		{9, 5, "0b1"},
+		{3, 100, "0b1"},
+	} {
+		if got, err := spctr.Stride(p.offset, p.step); err != nil {
+			t.Fatal(err)
+		} else if got.Bit() != p.want {
+			t.Errorf("Stride(%d, %d) expected %v, got %v", p.offset, p.step, p.want, got.Bit())
+		}
+	}
+
+	t.Logf("Exec: Stride() against Deinterleave()")
+	x, y, _ := Deinterleave(spctr)
+	if got, _ := spctr.Stride(0, 2); !got.Equal(x) {
+		t.Errorf("Expected %v, got %v", x.Bit(), got.Bit())
+	}
+	if got, _ := spctr.Stride(1, 2); !got.Equal(y) {
+		t.Errorf("Expected %v, got %v", y.Bit(), got.Bit())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: Stride()")
+	for _, p := range [][2]int{{0, 0}, {-1, 1}, {10, 1}} {
+		if _, err := spctr.Stride(p[0], p[1]); !errors.Is(err, ErrOutOfRange) {
+			t.Error("Error handling may not be appropriate.")
+		}
+	}
+}
+
+func TestCrossover(t *testing.T) {
+	x, _ := NewSpectrum(8)
+	y, _ := NewSpectrum(8)
+
+	x.SetString("11110000", 2)
+	y.SetString("00111100", 2)
+
+	t.Logf("Exec: Crossover()")
+	c1, c2, err := Crossover(x, y, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c1.Bit() != "0b00111000" || c2.Bit() != "0b11110100" {
+		t.Errorf("Expected (0b00111000, 0b11110100), got (%v, %v)", c1.Bit(), c2.Bit())
+	}
+	if Xor(c1, c2).Cmp(Xor(x, y)) != 0 || And(c1, c2).Cmp(And(x, y)) != 0 {
+		t.Errorf("Expected children to cover both parents' bits")
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: Crossover()")
+	z, _ := NewSpectrum(4)
+	if _, _, err := Crossover(x, z, 2); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+	for _, p := range []int{0, 8} {
+		if _, _, err := Crossover(x, y, p); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	var rows []*Spectrum
+	for _, r := range []string{"0011", "0101", "1111"} {
+		s, _ := NewSpectrum(4)
+		s.SetString(r, 2)
+		rows = append(rows, s)
+	}
+
+	t.Logf("Exec: Transpose()")
+	cols, err := Transpose(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"0b111", "0b101", "0b110", "0b100"}
+	for j, c := range cols {
+		if c.Bit() != want[j] {
+			t.Errorf("Column %d expected %s, got %s", j, want[j], c.Bit())
+		}
+	}
+	if back, _ := Transpose(cols); !back[1].Equal(rows[1]) {
+		t.Errorf("Expected %v, got %v", rows[1].Bit(), back[1].Bit())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: Transpose()")
+	short, _ := NewSpectrum(3)
+	if _, err := Transpose(append(rows, short)); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if _, err := Transpose(nil); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestHammingDistance(t *testing.T) {
+	x, _ := NewSpectrum(16)
+	x.SetUint64(0xA5C3)
+
+	t.Logf("Exec: HammingDistance()")
+	if got, err := HammingDistance(x, x.Copy()); err != nil {
+		t.Fatal(err)
+	} else if got != 0 {
+		t.Errorf("Identical expected %d, got %d", 0, got)
+	}
+
+	if got, err := HammingDistance(x, x.Not()); err != nil {
+		t.Fatal(err)
+	} else if got != 16 {
+		t.Errorf("Complement expected %d, got %d", 16, got)
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: HammingDistance()")
+	y, _ := NewSpectrum(8)
+	if _, err := HammingDistance(x, y); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	x, _ := NewSpectrum(8)
+	y, _ := NewSpectrum(8)
+
+	t.Logf("Exec: Jaccard(), Dice() with empty Spectrums")
+	if got, err := Jaccard(x, y); err != nil || got != 0 {
+		t.Errorf("Jaccard() expected 0, got %v (%v)", got, err)
+	}
+	if got, err := Dice(x, y); err != nil || got != 0 {
+		t.Errorf("Dice() expected 0, got %v (%v)", got, err)
+	}
+	if got, err := Cosine(x, y); err != nil || got != 0 {
+		t.Errorf("Cosine() expected 0, got %v (%v)", got, err)
+	}
+
+	x.SetString("11110000", 2)
+	y.SetString("00111100", 2)
+
+	t.Logf("Exec: Overlap()")
+	if got, err := Overlap(x, y); err != nil {
+		t.Fatal(err)
+	} else if got != 2 {
+		t.Errorf("Expected %d, got %d", 2, got)
+	}
+	wa, _ := NewSpectrum(200)
+	wb, _ := NewSpectrum(200)
+	wa.AdjustOnesCount(120)
+	wb.AdjustOnesCount(5)
+	for _, p := range [][2]*Spectrum{{wa, wb}, {wb, wa}} {
+		if got, _ := Overlap(p[0], p[1]); got != onesCount(And(wa, wb)) {
+			t.Errorf("Expected %d, got %d", onesCount(And(wa, wb)), got)
+		}
+	}
+
+	t.Logf("Exec: Jaccard()")
+	if got, _ := Jaccard(x, y); got != 2.0/6.0 {
+		t.Errorf("Expected %v, got %v", 2.0/6.0, got)
+	}
+
+	t.Logf("Exec: Dice()")
+	if got, _ := Dice(x, y); got != 0.5 {
+		t.Errorf("Expected %v, got %v", 0.5, got)
+	}
+
+	t.Logf("Exec: Cosine()")
+	if got, _ := Cosine(x, y); got != 0.5 {
+		t.Errorf("Expected %v, got %v", 0.5, got)
+	}
+	w, _ := NewSpectrum(8)
+	w.SetString("00110000", 2)
+	if got, _ := Cosine(x, w); math.Abs(got-2/math.Sqrt(8)) > 1e-12 {
+		t.Errorf("Expected %v, got %v", 2/math.Sqrt(8), got)
+	}
+
+	t.Logf("Exec: Tanimoto()")
+	if got, _ := Tanimoto(x, y); got != 2.0/6.0 {
+		t.Errorf("Expected %v, got %v", 2.0/6.0, got)
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: Jaccard(), Dice(), Cosine(), Overlap()")
+	z, _ := NewSpectrum(4)
+	if _, err := Jaccard(x, z); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if _, err := Dice(x, z); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if _, err := Cosine(x, z); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if _, err := Overlap(x, z); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	x, _ := NewSpectrum(70)
+	y, _ := NewSpectrum(70)
+	x.SetString("1010", 2)
+	y.SetString("1000000000000000000000000000000000000000000000000000000000000000000011", 2)
+
+	t.Logf("Exec: Diff()")
+	if got, err := Diff(x, y); err != nil {
+		t.Fatal(err)
+	} else if want := []int{0, 3, 69}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got, _ := Diff(x, x.Copy()); len(got) != 0 {
+		t.Errorf("Identical expected no positions, got %v", got)
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: Diff()")
+	z, _ := NewSpectrum(8)
+	if _, err := Diff(x, z); !errors.Is(err, ErrLengthMismatch) {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestDiffString(t *testing.T) {
+	x, _ := NewSpectrum(10)
+	y, _ := NewSpectrum(10)
+	x.SetString("1011001110", 2)
+	y.SetString("1001011111", 2)
+
+	t.Logf("Exec: DiffString()")
+	if got, err := DiffString(x, y); err != nil {
+		t.Fatal(err)
+	} else if got != "..^..^...^" {
+		t.Errorf("Expected %v, got %v", "..^..^...^", got)
+	}
+	if got, _ := DiffString(x, x.Copy()); got != strings.Repeat(".", 10) {
+		t.Errorf("Expected %v, got %v", strings.Repeat(".", 10), got)
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: DiffString()")
+	z, _ := NewSpectrum(8)
+	if _, err := DiffString(x, z); !errors.Is(err, ErrLengthMismatch) {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestSplit(t *testing.T) {
+	s, _ := NewSpectrum(11)
+	s.SetString("10101001100", 2)
+
+	t.Logf("Exec: Split()")
+	high, low, err := Split(s, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if high.Bit() != "0b1010100" || low.Bit() != "0b1100" {
+		t.Errorf("Expected (0b1010100, 0b1100), got (%v, %v)", high.Bit(), low.Bit())
+	}
+	if got, _ := Merge(high, low); !got.Equal(s) {
+		t.Errorf("Expected %v, got %v", s.Bit(), got.Bit())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: Split()")
+	for _, at := range []int{0, 11} {
+		if _, _, err := Split(s, at); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
+	}
+}
+
+func TestConcat(t *testing.T) {
+	x, _ := NewSpectrum(3)
+	y, _ := NewSpectrum(5)
+	z, _ := NewSpectrum(4)
+
+	x.SetString("101", 2)
+	y.SetString("00110", 2)
+	z.SetString("1001", 2)
+
+	t.Logf("Exec: Concat()")
+	got, err := Concat(x, y, z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xy, _ := Merge(x, y)
+	if want, _ := Merge(xy, z); !got.Equal(want) {
+		t.Errorf("Expected %v, got %v", want.Bit(), got.Bit())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: Concat()")
+	if _, err := Concat(); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestResize(t *testing.T) {
+	spctr, _ := NewSpectrum(8)
+	spctr.SetString("00101101", 2)
+
+	t.Logf("Exec: Resize()")
+	if got, err := spctr.Resize(12); err != nil {
+		t.Fatal(err)
+	} else if got.Bit() != "0b000000101101" {
+		t.Errorf("Expected 0b000000101101, got %v", got.Bit())
+	}
+	if got, err := spctr.Resize(6); err != nil {
+		t.Fatal(err)
+	} else if got.Bit() != "0b101101" {
+		t.Errorf("Expected 0b101101, got %v", got.Bit())
+	}
+
+	t.Logf("Exec: TruncateLossy()")
+	if got, err := spctr.TruncateLossy(4); err != nil {
+		t.Fatal(err)
+	} else if got.Bit() != "0b1101" {
+		t.Errorf("Expected 0b1101, got %v", got.Bit())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: Resize()")
+	if _, err := spctr.Resize(4); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestAppendBit(t *testing.T) {
+	spctr, _ := NewSpectrum(1)
+
+	t.Logf("Exec: AppendBit()")
+	for _, b := range []uint{1, 0, 2} {
+		spctr.AppendBit(b)
+	}
+	if spctr.Len() != 4 || spctr.Bit() != "0b1010" || spctr.OnesCount() != 2 {
+		t.Errorf("Expected %v, got %v", "0b1010", spctr.Bit())
+	}
+
+	t.Logf("Exec: PrependBit()")
+	spctr.PrependBit(1).PrependBit(0)
+	if spctr.Len() != 6 || spctr.Bit() != "0b101010" || spctr.OnesCount() != 3 {
+		t.Errorf("Expected %v, got %v", "0b101010", spctr.Bit())
+	}
+
+	t.Logf("Exec: AppendBit() beyond 64 bits")
+	for spctr.Len() < 100 {
+		spctr.AppendBit(uint(spctr.Len() % 2))
+	}
+	if got, _ := spctr.TestBit(99); !got || spctr.OnesCount() != 50 {
+		t.Errorf("Expected bit 99 to be set and %d ones, got %v", 50, spctr.Bit())
+	}
+
+	t.Logf("Exec: AppendBit(), PrependBit() at MaxLength")
+	defer func(l uint) { MaxLength = l }(MaxLength)
+	MaxLength = 8
+	full, _ := NewSpectrum(8)
+	full.SetUint64(0xA5)
+	if got := full.AppendBit(1).PrependBit(1); got != full {
+		t.Errorf("Expected the receiver to be returned")
+	}
+	if full.Len() != 8 || full.Uint64() != 0xA5 {
+		t.Errorf("Expected Spectrum to be unchanged, got %v", full.Bit())
+	}
+	data, _ := full.MarshalBinary()
+	var got Spectrum
+	if err := got.UnmarshalBinary(data); err != nil || !got.Equal(full) {
+		t.Errorf("Expected %v, got %v (%v)", full.Bit(), got.Bit(), err)
+	}
+}
+
+func TestDifference(t *testing.T) {
+	x, _ := NewSpectrum(8)
+	y, _ := NewSpectrum(8)
+
+	x.SetString("11001010", 2)
+	y.SetString("01101001", 2)
+
+	t.Logf("Exec: Difference()")
+	if got, err := Difference(x, y); err != nil {
+		t.Fatal(err)
+	} else if got.Bit() != "0b10000010" {
+		t.Errorf("Expected 0b10000010, got %v", got.Bit())
+	}
+
+	t.Logf("Exec: SymmetricDifference()")
+	if got, err := SymmetricDifference(x, y); err != nil {
+		t.Fatal(err)
+	} else if got.Bit() != "0b10100011" {
+		t.Errorf("Expected 0b10100011, got %v", got.Bit())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: Difference(), SymmetricDifference()")
+	z, _ := NewSpectrum(4)
+	if _, err := Difference(x, z); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if _, err := SymmetricDifference(x, z); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestBitwiseWith(t *testing.T) {
+	x, _ := NewSpectrum(8)
+	y, _ := NewSpectrum(8)
+	y.SetString("11000110", 2)
+
+	pattern := []struct {
+		name string
+		fn   func(s *Spectrum) func(*Spectrum) error
+		want string
+	}{
+		{"AndWith", func(s *Spectrum) func(*Spectrum) error { return s.AndWith }, "0b10000010"},
+		{"OrWith", func(s *Spectrum) func(*Spectrum) error { return s.OrWith }, "0b11101110"},
+		{"XorWith", func(s *Spectrum) func(*Spectrum) error { return s.XorWith }, "0b01101100"},
+	}
+
+	for _, p := range pattern {
+		t.Logf("Exec: %s()", p.name)
+		x.SetString("10101010", 2)
+		if err := p.fn(x)(y); err != nil {
+			t.Fatal(err)
+		} else if got := x.Bit(); got != p.want {
+			t.Errorf("%s() expected %v, got %v", p.name, p.want, got)
+		}
+
+		// -- exception usecase --
+		t.Logf("Error handling: %s()", p.name)
+		z, _ := NewSpectrum(4)
+		if err := p.fn(x)(z); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	x, _ := NewSpectrum(8)
+	y, _ := NewSpectrum(8)
+	z, _ := NewSpectrum(8)
+
+	x.SetString("11100000", 2)
+	y.SetString("01110001", 2)
+	z.SetString("01100110", 2)
+
+	t.Logf("Exec: OrAll()")
+	if got, err := OrAll(x, y, z); err != nil {
+		t.Fatal(err)
+	} else if got.Bit() != "0b11110111" {
+		t.Errorf("Expected 0b11110111, got %v", got.Bit())
+	}
+
+	t.Logf("Exec: AndAll()")
+	if got, err := AndAll(x, y, z); err != nil {
+		t.Fatal(err)
+	} else if got.Bit() != "0b01100000" {
+		t.Errorf("Expected 0b01100000, got %v", got.Bit())
+	} else if x.Bit() != "0b11100000" {
+		t.Errorf("Expected input to be unchanged, got %v", x.Bit())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: OrAll(), AndAll()")
+	w, _ := NewSpectrum(4)
+	if _, err := OrAll(); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if _, err := AndAll(x, w); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestMajority(t *testing.T) {
+	x, _ := NewSpectrum(8)
+	y, _ := NewSpectrum(8)
+	z, _ := NewSpectrum(8)
+
+	x.SetString("11100000", 2)
+	y.SetString("01110001", 2)
+	z.SetString("01100110", 2)
+
+	t.Logf("Exec: Majority()")
+	if got, err := Majority(x, y, z); err != nil {
+		t.Fatal(err)
+	} else if got.Bit() != "0b01100000" {
+		t.Errorf("Expected 0b01100000, got %v", got.Bit())
+	}
+
+	if got, _ := Majority(x, y); got.Bit() != "0b01100000" {
+		t.Errorf("Tie expected 0b01100000, got %v", got.Bit())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: Majority()")
+	w, _ := NewSpectrum(4)
+	if _, err := Majority(x, y, w); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	var specs []*Spectrum
+	for _, x := range []uint64{0x30, 0x05, 0xF0, 0x05, 0xF0} {
+		s, _ := NewSpectrum(8)
+		s.SetUint64(x)
+		specs = append(specs, s)
+	}
+
+	t.Logf("Exec: Max()")
+	if got, err := Max(specs...); err != nil {
+		t.Fatal(err)
+	} else if got.Uint64() != 0xF0 {
+		t.Errorf("Expected 0xf0, got %v", got.Hex())
+	}
+
+	t.Logf("Exec: Min()")
+	if got, err := Min(specs...); err != nil {
+		t.Fatal(err)
+	} else if got.Uint64() != 0x05 {
+		t.Errorf("Expected 0x05, got %v", got.Hex())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: Max(), Min()")
+	short, _ := NewSpectrum(4)
+	if _, err := Max(); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if _, err := Min(append(specs, short)...); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func BenchmarkAnd(b *testing.B) {
+	x, _ := NewSpectrum(1024)
+	y, _ := NewSpectrum(1024)
+	x.AdjustOnesCount(512)
+	y.AdjustOnesCount(512)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Set(And(x, y))
+	}
+}
+
+func BenchmarkAndWith(b *testing.B) {
+	x, _ := NewSpectrum(1024)
+	y, _ := NewSpectrum(1024)
+	x.AdjustOnesCount(512)
+	y.AdjustOnesCount(512)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.AndWith(y)
+	}
+}
+
+// --- encoding ---
+
+func TestMarshalBinary(t *testing.T) {
+	for _, l := range []uint{1, 7, 13, 64, 100} {
+		spctr, _ := NewSpectrum(l)
+		spctr.AdjustOnesCount(l / 2)
+
+		t.Logf("Exec: MarshalBinary()")
+		data, err := spctr.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Logf("Exec: UnmarshalBinary()")
+		var got Spectrum
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		} else if !got.Equal(spctr) {
+			t.Errorf("%dbits expected %v, got %v", l, spctr.Bit(), got.Bit())
+		}
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: UnmarshalBinary()")
+	var spctr Spectrum
+	for _, data := range [][]byte{
+		{0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 4, 0xFF},
+	} {
+		if err := spctr.UnmarshalBinary(data); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	var buf bytes.Buffer
+	var want []*Spectrum
+	for _, l := range []uint{7, 13, 64} {
+		spctr, _ := NewSpectrum(l)
+		spctr.AdjustOnesCount(l / 2)
+		want = append(want, spctr)
+
+		t.Logf("Exec: WriteTo()")
+		if n, err := spctr.WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		} else if data, _ := spctr.MarshalBinary(); n != int64(len(data)) {
+			t.Errorf("Expected %d bytes, got %d", len(data), n)
+		}
+	}
+
+	t.Logf("Exec: ReadFrom() until io.EOF")
+	var got []*Spectrum
+	for {
+		var s Spectrum
+		n, err := s.ReadFrom(&buf)
+		if err == io.EOF {
+			if n != 0 {
+				t.Errorf("Expected 0 bytes at io.EOF, got %d", n)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, &s)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d Spectrums, got %d", len(want), len(got))
+	}
+	for i, w := range want {
+		if !got[i].Equal(w) {
+			t.Errorf("Expected %v, got %v", w.Bit(), got[i].Bit())
+		}
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: ReadFrom()")
+	data, _ := want[2].MarshalBinary()
+	for _, l := range []int{4, len(data) - 1} {
+		var got Spectrum
+		if _, err := got.ReadFrom(bytes.NewReader(data[:l])); !errors.Is(err, ErrParse) {
+			t.Errorf("Expected ErrParse, got %v", err)
+		} else if got.bitVector != nil {
+			t.Errorf("Expected Spectrum to be unchanged, got %v", got.Bit())
+		}
+	}
+}
+
+func TestGob(t *testing.T) {
+	type record struct {
+		Name  string
+		Spctr *Spectrum
+		Specs []Spectrum
+	}
+
+	x, _ := NewSpectrum(13)
+	y, _ := NewSpectrum(100)
+	x.SetUint64(0x1A5)
+	y.AdjustOnesCount(50)
+	want := record{Name: "gob", Spctr: x, Specs: []Spectrum{*y, *x}}
+
+	t.Logf("Exec: GobEncode(), GobDecode()")
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(want); err != nil {
+		t.Fatal(err)
+	}
+	var got record
+	if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != want.Name || !got.Spctr.Equal(x) || len(got.Specs) != 2 {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	if !got.Specs[0].Equal(y) || !got.Specs[1].Equal(x) {
+		t.Errorf("Expected (%v, %v), got (%v, %v)", y.Hex(), x.Hex(), got.Specs[0].Hex(), got.Specs[1].Hex())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: GobDecode()")
+	var spctr Spectrum
+	if err := spctr.GobDecode([]byte{0, 0, 0}); !errors.Is(err, ErrParse) {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestBase64(t *testing.T) {
+	spctr, _ := NewSpectrum(12)
+	spctr.SetUint64(0xABC)
+
+	t.Logf("Exec: Base64()")
+	if got := spctr.Base64(); got != "AAAAAAAAAAwKvA==" {
+		t.Errorf("Expected %v, got %v", "AAAAAAAAAAwKvA==", got)
+	}
+
+	t.Logf("Exec: SetBase64()")
+	for _, l := range []uint{1, 7, 13, 64, 100} {
+		want, _ := NewSpectrum(l)
+		want.AdjustOnesCount(l / 2)
+		got, _ := NewSpectrum(8)
+		if _, err := got.SetBase64(want.Base64()); err != nil {
+			t.Fatal(err)
+		} else if !got.Equal(want) {
+			t.Errorf("%dbits expected %v, got %v", l, want.Bit(), got.Bit())
+		}
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: SetBase64()")
+	for _, str := range []string{"!!!", "AAAA", "AAAAAAAAAAwK"} {
+		if _, err := spctr.SetBase64(str); !errors.Is(err, ErrParse) {
+			t.Error("Error handling may not be appropriate.")
+		}
+	}
+	if spctr.Len() != 12 || spctr.Uint64() != 0xABC {
+		t.Errorf("Expected Spectrum to be unchanged, got %v", spctr.Bit())
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	spctr, _ := NewSpectrum(64)
+	spctr.SetUint64(bits32)
+
+	t.Logf("Exec: MarshalJSON()")
+	want := `{"length":64,"hex":"0x00000000ffffffff"}`
+	data, err := json.Marshal(spctr)
+	if err != nil {
+		t.Fatal(err)
+	} else if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+
+	t.Logf("Exec: UnmarshalJSON()")
+	var got Spectrum
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	} else if !got.Equal(spctr) {
+		t.Errorf("Expected %v, got %v", spctr.Bit(), got.Bit())
+	}
+
+	t.Logf("Exec: UnmarshalJSON() with null")
+	var rec struct{ Spctr Spectrum }
+	rec.Spctr = *spctr.Copy()
+	if err := json.Unmarshal([]byte(`{"Spctr":null}`), &rec); err != nil {
+		t.Fatal(err)
+	} else if !rec.Spctr.Equal(spctr) {
+		t.Errorf("Expected %v, got %v", spctr.Bit(), rec.Spctr.Bit())
+	}
+	if err := got.UnmarshalJSON([]byte(" null ")); err != nil {
+		t.Fatal(err)
+	} else if !got.Equal(spctr) {
+		t.Errorf("Expected %v, got %v", spctr.Bit(), got.Bit())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: UnmarshalJSON()")
+	for _, data := range []string{
+		`{"length":64}`,
+		`{"hex":"0xff"}`,
+		`{"length":4,"hex":"0xff"}`,
+		`{"length":8,"hex":"0xzz"}`,
+		`{"length":8,"hex":"0xff","extra":1}`,
+	} {
+		if err := json.Unmarshal([]byte(data), &got); err == nil {
+			t.Errorf("Case(%s): Error handling may not be appropriate.", data)
+		}
+	}
+}
+
+func TestSQL(t *testing.T) {
+	spctr, _ := NewSpectrum(13)
+	spctr.SetUint64(0x1A5)
+
+	t.Logf("Exec: Value()")
+	v, err := spctr.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := spctr.MarshalBinary(); !bytes.Equal(v.([]byte), want) {
+		t.Errorf("Expected % x, got % x", want, v)
+	}
+
+	t.Logf("Exec: Scan()")
+	for _, src := range []interface{}{v, string(v.([]byte))} {
+		var got Spectrum
+		if err := got.Scan(src); err != nil {
+			t.Fatal(err)
+		} else if !got.Equal(spctr) {
+			t.Errorf("Expected %v, got %v", spctr.Bit(), got.Bit())
+		}
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: Scan()")
+	var got Spectrum
+	if err := got.Scan(int64(1)); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestBytes(t *testing.T) {
+	spctr, _ := NewSpectrum(20)
+
+	t.Logf("Exec: SetBytes()")
+	if _, err := spctr.SetBytes([]byte{0x0A, 0xBC}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("Exec: Bytes()")
+	if got := spctr.Bytes(); !bytes.Equal(got, []byte{0x00, 0x0A, 0xBC}) {
+		t.Errorf("Expected % x, got % x", []byte{0x00, 0x0A, 0xBC}, got)
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: SetBytes()")
+	if _, err := spctr.SetBytes([]byte{0x10, 0x00, 0x00}); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestRunLength(t *testing.T) {
+	pattern := []struct {
+		bits string
+		want []int
+	}{
+		{"1110011", []int{0, 2, 2, 3}},
+		{"0001100", []int{2, 2, 3}},
+		{"0000000", []int{7}},
+		{"1111111", []int{0, 7}},
+	}
+
+	for _, p := range pattern {
+		spctr, _ := NewSpectrum(uint(len(p.bits)))
+		spctr.SetString(p.bits, 2)
+
+		t.Logf("Exec: RunLengthEncode()")
+		runs := spctr.RunLengthEncode()
+		if !reflect.DeepEqual(runs, p.want) {
+			t.Errorf("Case(0b%s) expected %v, got %v", p.bits, p.want, runs)
+		}
+
+		t.Logf("Exec: RunLengthDecode()")
+		if got, err := RunLengthDecode(runs); err != nil {
+			t.Fatal(err)
+		} else if !got.Equal(spctr) {
+			t.Errorf("Expected %v, got %v", spctr.Bit(), got.Bit())
+		}
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: RunLengthDecode()")
+	for _, runs := range [][]int{{}, {0, 0}, {3, -1}} {
+		if _, err := RunLengthDecode(runs); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
+	}
+}
+
+// --- SafeSpectrum ---
+
+func TestSafeSpectrum(t *testing.T) {
+	ss, err := NewSafeSpectrum(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("Exec: SafeSpectrum concurrently")
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 32; i++ {
+				ss.SetBit(g*32 + i)
+				ss.OnesCount()
+				ss.Uint64n(4)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if got := ss.OnesCount(); got != 256 {
+		t.Errorf("Expected %d, got %d", 256, got)
+	}
+
+	ss.Do(func(s *Spectrum) {
+		s.ClearRange(0, 128)
+	})
+	if got := ss.Spectrum(); got.OnesCount() != 128 {
+		t.Errorf("Expected %d, got %d", 128, got.OnesCount())
+	}
+}
+
+// --- sequence ---
+
+func TestLFSRNext(t *testing.T) {
+	pattern := []struct {
+		length uint
+		taps   []int
+	}{
+		{4, []int{0, 1}},
+		{5, []int{0, 2}},
+		{8, []int{0, 2, 3, 4}},
+	}
+
+	t.Logf("Exec: LFSRNext()")
+	for _, p := range pattern {
+		spctr, _ := NewSpectrum(p.length)
+		spctr.SetUint64(1)
+
+		period := 1<<p.length - 1
+		seen := map[uint64]bool{}
+		state := spctr
+		for i := 0; i < period; i++ {
+			seen[state.Uint64()] = true
+			next, err := state.LFSRNext(p.taps)
+			if err != nil {
+				t.Fatal(err)
+			}
+			state = next
+		}
+		if len(seen) != period || !state.Equal(spctr) {
+			t.Errorf("%dbits expected period %d, got %d distinct states", p.length, period, len(seen))
+		}
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: LFSRNext()")
+	spctr, _ := NewSpectrum(4)
+	if _, err := spctr.LFSRNext([]int{0, 4}); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestCAStep(t *testing.T) {
+	pattern := []struct {
+		rule uint8
+		rows []string
+	}{
+		{30, []string{"000010000", "000111000", "001100100", "011011110"}},
+		{90, []string{"000010000", "000101000", "001000100", "010101010"}},
+		{90, []string{"100000000", "010000001", "001000010"}},
+	}
+
+	t.Logf("Exec: CAStep()")
+	for _, p := range pattern {
+		spctr, _ := NewSpectrum(uint(len(p.rows[0])))
+		spctr.SetString(p.rows[0], 2)
+		for _, want := range p.rows[1:] {
+			spctr = spctr.CAStep(p.rule)
+			if got := spctr.Bit(); got != "0b"+want {
+				t.Errorf("Rule %d expected 0b%s, got %s", p.rule, want, got)
+			}
+		}
+	}
+}
+
+func TestAutocorrelation(t *testing.T) {
+	state, _ := NewSpectrum(4)
+	state.SetUint64(1)
+
+	seq, _ := NewSpectrum(15)
+	for i := 0; i < 15; i++ {
+		if ok, _ := state.TestBit(0); ok {
+			seq.SetBit(i)
+		}
+		state, _ = state.LFSRNext([]int{0, 1})
+	}
+
+	t.Logf("Exec: Autocorrelation()")
+	if got := seq.Autocorrelation(0); got != 15 {
+		t.Errorf("Shift 0 expected %d, got %d", 15, got)
+	}
+	for k := 1; k < 15; k++ {
+		if got := seq.Autocorrelation(k); got != -1 {
+			t.Errorf("Shift %d expected %d, got %d", k, -1, got)
+		}
+		if got := seq.Autocorrelation(-k); got != -1 {
+			t.Errorf("Shift %d expected %d, got %d", -k, -1, got)
+		}
+	}
+}
+
+func TestBlockEntropy(t *testing.T) {
+	spctr, _ := NewSpectrum(17)
+
+	t.Logf("Exec: BlockEntropy()")
+	if got, err := spctr.BlockEntropy(2); err != nil {
+		t.Fatal(err)
+	} else if got != 0 {
+		t.Errorf("Uniform expected %v, got %v", 0, got)
+	}
+
+	spctr.SetString("1"+"1110010011100100", 2)
+	if got, _ := spctr.BlockEntropy(2); got != 2 {
+		t.Errorf("All 2bit blocks expected %v, got %v", 2, got)
+	}
+	if got, _ := spctr.BlockEntropy(4); got != 1 {
+		t.Errorf("Two 4bit blocks expected %v, got %v", 1, got)
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: BlockEntropy()")
+	for _, b := range []int{0, 18} {
+		if _, err := spctr.BlockEntropy(b); err == nil {
+			t.Error("Error handling may not be appropriate.")
+		}
+	}
+}
+
+func TestPeriod(t *testing.T) {
+	pattern := []struct {
+		in   string
+		want int
+	}{
+		{"101010", 2},
+		{"1010101010101010101010101010101010101010101010101010101010101010101010", 2},
+		{"110110110110", 3},
+		{"000000", 1},
+		{"111", 1},
+		{"1", 1},
+		{"10110", 5},
+		{"100100", 3},
+		{"10011001", 4},
+	}
+
+	t.Logf("Exec: Period()")
+	for _, p := range pattern {
+		spctr, _ := NewSpectrum(uint(len(p.in)))
+		spctr.SetString(p.in, 2)
+		if got := spctr.Period(); got != p.want {
+			t.Errorf("%s expected %d, got %d", p.in, p.want, got)
+		}
+	}
+
+	t.Logf("Exec: Period() of LFSR output")
+	lfsr, _ := NewSpectrum(4)
+	lfsr.SetUint64(1)
+	out, _ := NewSpectrum(45)
+	for i := 0; i < 45; i++ {
+		if lfsr.bitVector.Bit(0) == 1 {
+			out.SetBit(i)
+		}
+		lfsr, _ = lfsr.LFSRNext([]int{0, 1})
+	}
+	if got := out.Period(); got != 15 {
+		t.Errorf("Expected %d, got %d", 15, got)
+	}
+}
+
+// --- GF(2) polynomial arithmetic ---
+
+func TestCLMul(t *testing.T) {
+	x, _ := NewSpectrum(8)
+	y, _ := NewSpectrum(8)
+
+	x.SetUint64(0x57)
+	y.SetUint64(0x83)
+
+	t.Logf("Exec: CLMul()")
+	p, err := CLMul(x, y)
+	if err != nil {
+		t.Fatal(err)
+	} else if p.Len() != 16 || p.Uint64() != 0x2B79 {
+		t.Errorf("Expected 0x2b79, got %v", p.Hex())
+	}
+
+	t.Logf("Exec: GF2Mod()")
+	aes, _ := NewSpectrum(9)
+	aes.SetUint64(0x11B)
+	if got, err := p.GF2Mod(aes); err != nil {
+		t.Fatal(err)
+	} else if got.Len() != 8 || got.Uint64() != 0xC1 {
+		t.Errorf("Expected 0xc1, got %v", got.Hex())
+	}
+
+	// GF(2^3) with x^3 + x + 1
+	gf8, _ := NewSpectrum(4)
+	gf8.SetUint64(0xB)
+	table := [8][8]uint64{
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 1, 2, 3, 4, 5, 6, 7},
+		{0, 2, 4, 6, 3, 1, 7, 5},
+		{0, 3, 6, 5, 7, 4, 1, 2},
+		{0, 4, 3, 7, 6, 2, 5, 1},
+		{0, 5, 1, 4, 2, 7, 3, 6},
+		{0, 6, 7, 1, 5, 3, 2, 4},
+		{0, 7, 5, 2, 1, 6, 4, 3},
+	}
+	a, _ := NewSpectrum(3)
+	b, _ := NewSpectrum(3)
+	for i := range table {
+		for j, want := range table[i] {
+			a.SetUint64(uint64(i))
+			b.SetUint64(uint64(j))
+			p, _ := CLMul(a, b)
+			if got, _ := p.GF2Mod(gf8); got.Uint64() != want {
+				t.Errorf("%d * %d expected %d, got %d", i, j, want, got.Uint64())
+			}
+		}
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: GF2Mod()")
+	zero, _ := NewSpectrum(4)
+	if _, err := p.GF2Mod(zero); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestDot(t *testing.T) {
+	// Hamming(7,4)符号のパリティ検査行列
+	var h []*Spectrum
+	for _, row := range []string{"1010101", "1100110", "1111000"} {
+		r, _ := NewSpectrum(7)
+		r.SetString(row, 2)
+		h = append(h, r)
+	}
+	syndrome := func(c *Spectrum) int {
+		var n int
+		for i, r := range h {
+			d, err := Dot(r, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			n |= d << i
+		}
+		return n
+	}
+
+	t.Logf("Exec: Dot()")
+	c, _ := NewSpectrum(7)
+	for _, word := range []string{"0000000", "1111111", "0110011", "1010101"} {
+		c.SetString(word, 2)
+		if got := syndrome(c); got != 0 {
+			t.Errorf("Codeword %s expected syndrome 0, got %d", word, got)
+		}
+	}
+	for i := 0; i < 7; i++ {
+		c.Fill()
+		c.ToggleBit(i)
+		if got := syndrome(c); got != i+1 {
+			t.Errorf("Error at bit %d expected syndrome %d, got %d", i, i+1, got)
+		}
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: Dot()")
+	short, _ := NewSpectrum(6)
+	if _, err := Dot(c, short); !errors.Is(err, ErrLengthMismatch) {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestCRC(t *testing.T) {
+	msg, _ := NewSpectrumFromBytes([]byte("123456789"), 72)
+
+	pattern := []struct {
+		name   string
+		length uint
+		poly   uint64
+		want   uint64
+	}{
+		{"CRC-8/SMBUS", 9, 0x107, 0xF4},
+		{"CRC-16/XMODEM", 17, 0x11021, 0x31C3},
+		{"CRC-32 with zero init", 33, 0x104C11DB7, 0x89A1897F},
+	}
+
+	t.Logf("Exec: CRC()")
+	for _, p := range pattern {
+		poly, _ := NewSpectrum(p.length)
+		poly.SetUint64(p.poly)
+		got, err := CRC(msg, poly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Len() != int(p.length)-1 || got.Uint64() != p.want {
+			t.Errorf("%s expected %x, got %v", p.name, p.want, got.Hex())
+		}
+
+		// CRC値を連結した符号語の余りは0になります．
+		cw, _ := Merge(msg, got)
+		if r, _ := cw.GF2Mod(poly); !r.IsZero() {
+			t.Errorf("%s codeword expected zero remainder, got %v", p.name, r.Hex())
+		}
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: CRC()")
+	poly, _ := NewSpectrum(9)
+	poly.SetUint64(0x07)
+	if _, err := CRC(msg, poly); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("Error handling may not be appropriate.")
+	}
+	one, _ := NewSpectrum(1)
+	one.Fill()
+	if _, err := CRC(msg, one); !errors.Is(err, ErrInvalidArgument) {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+// --- BloomFilter ---
+
+func TestBloomFilter(t *testing.T) {
+	const m, n, k = 10000, 1000, 7
+	bf := NewBloomFilter(m, k)
+
+	t.Logf("Exec: Add(), Test()")
+	for i := 0; i < n; i++ {
+		bf.Add([]byte(fmt.Sprintf("member-%d", i)))
+	}
+	for i := 0; i < n; i++ {
+		if !bf.Test([]byte(fmt.Sprintf("member-%d", i))) {
+			t.Fatalf("Expected member-%d to be present", i)
+		}
+	}
+
+	var fp int
+	const trials = 20000
+	for i := 0; i < trials; i++ {
+		if bf.Test([]byte(fmt.Sprintf("other-%d", i))) {
+			fp++
+		}
+	}
+
+	want := math.Pow(1-math.Exp(-float64(k*n)/m), k)
+	if got := float64(fp) / trials; 2*want < got {
+		t.Errorf("False-positive rate expected near %v, got %v", want, got)
+	}
+}
+
+// --- SparseSpectrum ---
+
+func TestSparseSpectrum(t *testing.T) {
+	x, _ := NewSpectrum(200)
+	y, _ := NewSpectrum(200)
+	x.AdjustOnesCount(30)
+	y.AdjustOnesCount(30)
+
+	sx, sy := x.Sparse(), y.Sparse()
+
+	t.Logf("Exec: SparseSpectrum methods against Spectrum")
+	for _, bv := range []BitVector{x, sx} {
+		bv.SetBit(0)
+		bv.SetBit(199)
+		bv.ClearBit(100)
+		bv.ToggleBit(50)
+		bv.ToggleBit(51)
+		bv.ToggleBit(51)
+	}
+	if !reflect.DeepEqual(x.Positions(), sx.Positions()) || x.OnesCount() != sx.OnesCount() {
+		t.Errorf("Expected %v, got %v", x.Positions(), sx.Positions())
+	}
+	for i := 0; i < 200; i++ {
+		want, _ := x.TestBit(i)
+		if got, _ := sx.TestBit(i); got != want {
+			t.Errorf("TestBit(%d) expected %v, got %v", i, want, got)
+		}
+	}
+
+	t.Logf("Exec: And(), Or()")
+	and, _ := sx.And(sy)
+	if want, _ := AndS(x, y); !reflect.DeepEqual(and.Positions(), want.Positions()) {
+		t.Errorf("And() expected %v, got %v", want.Positions(), and.Positions())
+	}
+	or, _ := sx.Or(sy)
+	if want, _ := OrS(x, y); !reflect.DeepEqual(or.Positions(), want.Positions()) {
+		t.Errorf("Or() expected %v, got %v", want.Positions(), or.Positions())
+	}
+
+	t.Logf("Exec: Dense()")
+	if got, err := sx.Dense(); err != nil {
+		t.Fatal(err)
+	} else if !got.Equal(x) {
+		t.Errorf("Expected %v, got %v", x.Hex(), got.Hex())
+	}
+
+	// -- exception usecase --
+	t.Logf("Error handling: SparseSpectrum")
+	if _, err := NewSparseSpectrum(0); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if err := sx.SetBit(200); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+	short, _ := NewSparseSpectrum(10)
+	if _, err := sx.And(short); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+	if _, err := sx.Or(short); err == nil {
+		t.Error("Error handling may not be appropriate.")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	s, _ := NewSpectrum(8)
+	short, _ := NewSpectrum(4)
+
+	t.Logf("Exec: errors.Is() with sentinel errors")
+	_, errLength := NewSpectrum(0)
+	_, errTooLong := s.SetUint64(0x100)
+	_, errNegative := s.Set(big.NewInt(-1))
+	_, errParse := s.SetString("zz", 10)
+	_, errMismatch := HammingDistance(s, short)
+	errRange := s.SetBit(-1)
+	_, errEmpty := OrAll()
+	zero, _ := NewSpectrum(4)
+	_, errArgument := s.GF2Mod(zero)
+	cases := []struct {
+		err    error
+		target error
+	}{
+		{errLength, ErrInvalidLength},
+		{errTooLong, ErrTooLong},
+		{errNegative, ErrNegative},
+		{errParse, ErrParse},
+		{errMismatch, ErrLengthMismatch},
+		{errRange, ErrOutOfRange},
+		{errEmpty, ErrEmpty},
+		{errArgument, ErrInvalidArgument},
+	}
+	for i, c := range cases {
+		if !errors.Is(c.err, c.target) {
+			t.Errorf("case %d: expected %v, got %v", i, c.target, c.err)
+		}
+	}
+	if errors.Is(errParse, ErrTooLong) {
+		t.Errorf("Expected %v not to be %v", errParse, ErrTooLong)
+	}
+}
+
+func BenchmarkDenseLowDensity(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s, _ := NewSpectrum(1000000)
+		for j := 0; j < 1000; j++ {
+			s.SetBit(j * 997)
+		}
+		s.OnesCount()
+	}
+}
+
+func BenchmarkSparseLowDensity(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s, _ := NewSparseSpectrum(1000000)
+		for j := 0; j < 1000; j++ {
+			s.SetBit(j * 997)
+		}
+		s.OnesCount()
 	}
 }